@@ -0,0 +1,131 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AlertRule routes posts from a set of subreddits that satisfy a matcher to a sink
+type AlertRule struct {
+	Name       string
+	Subreddits []string
+	Match      func(post *Post) bool
+	Sink       Sink
+}
+
+// AlertEngine serves many alert rules from a single stream of new posts
+type AlertEngine struct {
+	Interval time.Duration
+	rules    []AlertRule
+}
+
+// NewAlertEngine create a new alert engine polling for new posts at the provided interval
+func NewAlertEngine(interval time.Duration) *AlertEngine {
+
+	return &AlertEngine{Interval: interval, rules: make([]AlertRule, 0)}
+}
+
+// AddRule add a rule to the engine. Rules must be added before calling Run
+func (me *AlertEngine) AddRule(rule AlertRule) {
+
+	me.rules = append(me.rules, rule)
+}
+
+// Run poll the union of all rules' subreddits until the context is cancelled, writing each matching post to the sink of every rule it matches. Listing and sink errors do not stop the engine; they are delivered on the returned channel if the consumer is receiving from it and dropped otherwise
+func (me *AlertEngine) Run(ctx context.Context) <-chan error {
+
+	errs := make(chan error, 1)
+
+	rules := make(map[string][]AlertRule)
+
+	for _, rule := range me.rules {
+		for _, subreddit := range rule.Subreddits {
+			key := strings.ToLower(subreddit)
+			rules[key] = append(rules[key], rule)
+		}
+	}
+
+	subreddits := make([]string, 0, len(rules))
+
+	for subreddit := range rules {
+		subreddits = append(subreddits, subreddit)
+	}
+
+	posts, streamErrs := StreamPosts(ctx, me.Interval, subreddits...)
+
+	go func() {
+
+		defer close(errs)
+
+		for {
+			select {
+			case err, ok := <-streamErrs:
+
+				if !ok {
+					streamErrs = nil
+					continue
+				}
+
+				sendError(errs, err)
+			case post, ok := <-posts:
+
+				if !ok {
+					return
+				}
+
+				for _, rule := range rules[strings.ToLower(post.Subreddit)] {
+
+					if rule.Match != nil && !rule.Match(&post) {
+						continue
+					}
+
+					if err := writeAlert(rule, post); err != nil {
+						sendError(errs, err)
+					}
+				}
+			}
+		}
+	}()
+
+	return errs
+}
+
+func writeAlert(rule AlertRule, post Post) error {
+
+	err := rule.Sink.Write(post)
+
+	if err == nil {
+		err = rule.Sink.Flush()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Alert rule '%s' failed to deliver post %s: %s", rule.Name, post.ID, err.Error())
+	}
+
+	return nil
+}
+
+// KeywordMatcher create a matcher accepting posts whose title or text contains any of the keywords as a whole word, ignoring case
+func KeywordMatcher(keywords ...string) func(post *Post) bool {
+
+	if len(keywords) == 0 {
+		return func(post *Post) bool {
+			return false
+		}
+	}
+
+	quoted := make([]string, len(keywords))
+
+	for i, keyword := range keywords {
+		quoted[i] = regexp.QuoteMeta(keyword)
+	}
+
+	pattern := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+
+	return func(post *Post) bool {
+		return pattern.MatchString(post.Title) || pattern.MatchString(post.Text)
+	}
+}
@@ -0,0 +1,131 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// apiMaxContext the maximum number of parent comments reddit includes above a focused comment
+const apiMaxContext = 8
+
+// Ancestors resolve the chain of parent comments above a comment, up to the post it was made on. The parents are returned from the top level comment down to the comment's direct parent. Each request retrieves up to eight levels of parents
+func Ancestors(ctx context.Context, comment *Comment) (*Post, []Comment, error) {
+
+	chain := make([]Comment, 0)
+
+	current := *comment
+
+	var post *Post
+
+	for strings.HasPrefix(current.ParentID, apiObjectTypeComment+"_") {
+
+		threadPost, comments, _, err := getCommentContext(ctx, current.Subreddit, current.PostID, current.ParentID, apiMaxContext)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		post = threadPost
+
+		byFullname := make(map[string]Comment)
+
+		for _, c := range comments {
+			byFullname[c.Fullname()] = c
+		}
+
+		parent, ok := byFullname[current.ParentID]
+
+		if !ok {
+			return nil, nil, fmt.Errorf("Parent comment %s not found", current.ParentID)
+		}
+
+		for ok {
+
+			chain = append([]Comment{parent}, chain...)
+			current = parent
+
+			parent, ok = byFullname[current.ParentID]
+		}
+	}
+
+	if post == nil {
+
+		posts, err := getPostsByID([]string{postFullname(current.PostID)})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(posts) == 0 {
+			return nil, nil, fmt.Errorf("Post %s not found", current.PostID)
+		}
+
+		post = &posts[0]
+	}
+
+	return post, chain, nil
+}
+
+// Subtree retrieve the replies under a single comment without downloading the rest of the thread. Returns the replies in thread order along with the IDs of further replies that were not included
+func Subtree(ctx context.Context, postID, commentID string) ([]Comment, []string, error) {
+
+	_, comments, more, err := getCommentContext(ctx, "", postID, commentID, 0)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replies := make([]Comment, 0)
+
+	descendants := map[string]bool{string(AppendFullname(nil, apiObjectTypeComment, commentID)): true}
+
+	for _, comment := range comments {
+
+		if !descendants[comment.ParentID] {
+			continue
+		}
+
+		descendants[comment.Fullname()] = true
+		replies = append(replies, comment)
+	}
+
+	return replies, more, nil
+}
+
+// getCommentContext retrieve a post and the comments around one of its comments, including up to depth levels of parents
+func getCommentContext(ctx context.Context, subreddit, postID, commentID string, depth int) (*Post, []Comment, []string, error) {
+
+	return getThread(ctx, getCommentContextURL(subreddit, postID, commentID, depth))
+}
+
+func getCommentContextURL(subreddit, postID, commentID string, depth int) *url.URL {
+
+	redditURL := getBaseURL()
+
+	if hasFullnamePrefix(postID) {
+		postID = postID[3:]
+	}
+
+	if hasFullnamePrefix(commentID) {
+		commentID = commentID[3:]
+	}
+
+	if subreddit == "" {
+		redditURL.Path = fmt.Sprintf("/comments/%s/_/%s.json", postID, commentID)
+	} else {
+		redditURL.Path = fmt.Sprintf("/r/%s/comments/%s/_/%s.json", subreddit, postID, commentID)
+	}
+
+	if depth > 0 {
+		q := redditURL.Query()
+
+		q.Set("context", strconv.Itoa(depth))
+
+		redditURL.RawQuery = q.Encode()
+	}
+
+	return redditURL
+}
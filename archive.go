@@ -0,0 +1,339 @@
+package rscraper
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Archiver retrieves as much of a subreddit's post history as reddit's listings allow
+type Archiver struct {
+	Subreddit string
+
+	// Shard also walk the top of all time, year, month and week listings in parallel, reaching older posts that fell out of the new listing
+	Shard bool
+
+	// Windows additional search windows to backfill, reaching posts beyond the listing cap. See PlanBackfill
+	Windows []BackfillWindow
+
+	// Cache when set, listing pages are read from and saved to the cache, so an interrupted archive can be run again without refetching pages
+	Cache PageCache
+
+	// MaxBuffered the maximum number of posts Stream holds in memory ahead of its consumer. Buffered posts also count against the package's memory budget, see SetMemoryBudget
+	MaxBuffered int
+
+	// Manifest set by Run to a record of how the archive was produced
+	Manifest *Manifest
+
+	// Truncated set by Run when at least one listing hit reddit's listing cap, meaning the archive is missing older posts
+	Truncated bool
+}
+
+type archiveShard struct {
+	listingType string
+	topType     string
+}
+
+// NewArchiver create a new archiver for a subreddit
+func NewArchiver(subreddit string) *Archiver {
+
+	return &Archiver{Subreddit: subreddit}
+}
+
+// Run retrieve every reachable post in the subreddit, newest first and without duplicates
+func (me *Archiver) Run() ([]Post, error) {
+
+	posts := make([]Post, 0)
+
+	stream, errs := me.Stream(context.Background())
+
+	for post := range stream {
+		posts = append(posts, post)
+	}
+
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	sortPosts(posts)
+
+	return posts, nil
+}
+
+// Stream retrieve every reachable post in the subreddit without duplicates, in no particular order. At most MaxBuffered posts, and no more than the memory budget allows, are held ahead of the consumer; fetching pauses until the consumer catches up. The error channel receives a single value once the post channel is closed
+func (me *Archiver) Stream(ctx context.Context) (<-chan Post, <-chan error) {
+
+	buffered := make(chan Post, me.MaxBuffered)
+	stream := make(chan Post)
+	errs := make(chan error, 1)
+
+	var hold budgetHold
+	var holdMutex sync.Mutex
+
+	go func() {
+
+		defer close(errs)
+
+		err := me.stream(ctx, buffered, func(post *Post) error {
+
+			var postHold budgetHold
+
+			if err := postHold.acquire(ctx, 1, postSize(post)); err != nil {
+				return err
+			}
+
+			holdMutex.Lock()
+			hold.items += postHold.items
+			hold.bytes += postHold.bytes
+			holdMutex.Unlock()
+
+			return nil
+		})
+
+		close(buffered)
+
+		errs <- err
+	}()
+
+	go func() {
+
+		defer close(stream)
+
+		defer hold.releaseAll()
+
+		for post := range buffered {
+
+			select {
+			case stream <- post:
+			case <-ctx.Done():
+			}
+
+			holdMutex.Lock()
+			hold.release(1, postSize(&post))
+			holdMutex.Unlock()
+		}
+	}()
+
+	return stream, errs
+}
+
+// RunTo write every reachable post in the subreddit to sink, flushing it once the archive is complete
+func (me *Archiver) RunTo(ctx context.Context, sink Sink) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, errs := me.Stream(ctx)
+
+	for post := range stream {
+		if err := sink.Write(post); err != nil {
+
+			cancel()
+
+			for range stream {
+			}
+
+			return err
+		}
+	}
+
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	return sink.Flush()
+}
+
+func (me *Archiver) stream(ctx context.Context, stream chan<- Post, reserve func(post *Post) error) error {
+
+	parent := ctx
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	manifest := newManifest(me.Subreddit)
+
+	shards := me.shards()
+
+	counts := make([]int, len(shards)+1)
+	errs := make([]error, len(shards)+1)
+
+	var mutex sync.Mutex
+
+	seen := make(map[string]bool)
+
+	emit := func(post Post) bool {
+
+		mutex.Lock()
+
+		if seen[post.ID] {
+			mutex.Unlock()
+			return true
+		}
+
+		seen[post.ID] = true
+		manifest.observe(post)
+
+		mutex.Unlock()
+
+		if reserve(&post) != nil {
+			return false
+		}
+
+		select {
+		case stream <- post:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	for i, shard := range shards {
+
+		wg.Add(1)
+
+		go func(i int, shard archiveShard) {
+
+			defer wg.Done()
+
+			release := acquireSubreddit(me.Subreddit)
+			defer release()
+
+			iterator := NewPostIteratorContext(ctx, me.Subreddit, shard.listingType, shard.topType)
+			defer iterator.Close()
+
+			iterator.Cache = me.Cache
+
+			for iterator.Next() {
+
+				counts[i]++
+
+				if !emit(iterator.Post()) {
+					return
+				}
+			}
+
+			errs[i] = iterator.Err()
+
+			if errs[i] == nil && iterator.Capped() {
+				errs[i] = &ListingCapError{Subreddit: me.Subreddit, ListingType: shard.listingType, Count: iterator.Count()}
+			}
+
+			if errs[i] != nil {
+				if _, capped := errs[i].(*ListingCapError); !capped {
+					cancel()
+				}
+			}
+		}(i, shard)
+	}
+
+	if len(me.Windows) > 0 {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			release := acquireSubreddit(me.Subreddit)
+			defer release()
+
+			errs[len(shards)] = backfill(me.Subreddit, me.Windows, func(posts []Post) bool {
+
+				for _, post := range posts {
+
+					counts[len(shards)]++
+
+					if !emit(post) {
+						return false
+					}
+				}
+
+				return true
+			})
+
+			if _, capped := errs[len(shards)].(*ListingCapError); errs[len(shards)] != nil && !capped {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	me.Truncated = false
+
+	for i, err := range errs {
+
+		_, capped := err.(*ListingCapError)
+
+		if err != nil && !capped {
+			return err
+		}
+
+		me.Truncated = me.Truncated || capped
+
+		if i < len(shards) {
+			manifest.addShard(me.Subreddit, shards[i], counts[i], capped)
+		} else if len(me.Windows) > 0 {
+			manifest.addWindows(me.Subreddit, me.Windows, counts[i])
+		}
+	}
+
+	if err := parent.Err(); err != nil {
+		return err
+	}
+
+	manifest.finish()
+
+	me.Manifest = manifest
+
+	return nil
+}
+
+func (me *Archiver) shards() []archiveShard {
+
+	shards := []archiveShard{{listingType: ListingTypeNew}}
+
+	if me.Shard {
+		shards = append(shards,
+			archiveShard{listingType: ListingTypeTop, topType: ListingTopAllTime},
+			archiveShard{listingType: ListingTypeTop, topType: ListingTopPastYear},
+			archiveShard{listingType: ListingTypeTop, topType: ListingTopPastMonth},
+			archiveShard{listingType: ListingTypeTop, topType: ListingTopPastWeek},
+		)
+	}
+
+	return shards
+}
+
+func mergePosts(lists ...[]Post) []Post {
+
+	posts := make([]Post, 0)
+
+	seen := make(map[string]bool)
+
+	for _, list := range lists {
+		for _, post := range list {
+
+			if seen[post.ID] {
+				continue
+			}
+
+			seen[post.ID] = true
+			posts = append(posts, post)
+		}
+	}
+
+	sortPosts(posts)
+
+	return posts
+}
+
+func sortPosts(posts []Post) {
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedUTC > posts[j].CreatedUTC
+	})
+}
@@ -0,0 +1,85 @@
+package rscraper
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	apiOAuthHost      = "oauth.reddit.com"
+	apiAccessTokenURL = "https://www.reddit.com/api/v1/access_token"
+)
+
+var (
+	tokenMutex sync.RWMutex
+	token      string
+)
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Error       string `json:"error"`
+}
+
+// SetAccessToken authenticate all further requests with an OAuth2 bearer token. Authenticated requests are sent to oauth.reddit.com, and the token is redacted from errors and dumps. Passing an empty token reverts to anonymous requests
+func SetAccessToken(accessToken string) {
+
+	RedactSecret(accessToken)
+
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+
+	token = accessToken
+}
+
+// GetAccessToken retrieve an OAuth2 bearer token for a reddit "script" application using the account's username and password
+func GetAccessToken(clientID, clientSecret, username, password string) (string, error) {
+
+	RedactSecret(clientSecret)
+	RedactSecret(password)
+
+	form := url.Values{}
+
+	form.Set("grant_type", "password")
+	form.Set("username", username)
+	form.Set("password", password)
+
+	req, err := http.NewRequest("POST", apiAccessTokenURL, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return "", err
+	}
+
+	req.SetBasicAuth(clientID, clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	bytes, err := do(req)
+
+	if err != nil {
+		return "", err
+	}
+
+	var result accessTokenResponse
+
+	if err = unmarshal(bytes, &result); err != nil {
+		return "", err
+	}
+
+	if result.AccessToken == "" {
+		return "", errors.New("Reddit did not return an access token: " + result.Error)
+	}
+
+	RedactSecret(result.AccessToken)
+
+	return result.AccessToken, nil
+}
+
+func accessToken() string {
+
+	tokenMutex.RLock()
+	defer tokenMutex.RUnlock()
+
+	return token
+}
@@ -0,0 +1,363 @@
+package rscraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiDeletedAuthor the author reddit reports for posts and comments whose account was deleted
+const apiDeletedAuthor = "[deleted]"
+
+const (
+	// AuthorStatusActive the author's account is in good standing
+	AuthorStatusActive = "active"
+
+	// AuthorStatusDeleted the author deleted their account
+	AuthorStatusDeleted = "deleted"
+
+	// AuthorStatusSuspended the author's account was suspended by reddit
+	AuthorStatusSuspended = "suspended"
+
+	// AuthorStatusShadowbanned the author's name is shown on their posts and comments but their profile is not found, which is how shadowbanned accounts appear
+	AuthorStatusShadowbanned = "shadowbanned"
+)
+
+// User a reddit account
+type User struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	LinkKarma    int     `json:"link_karma"`
+	CommentKarma int     `json:"comment_karma"`
+	TotalKarma   int     `json:"total_karma"`
+	CreatedUTC   float64 `json:"created_utc"`
+	IsSuspended  bool    `json:"is_suspended"`
+	CreatedOn    time.Time
+}
+
+// AccountAge how long ago the account was created
+func (me *User) AccountAge() time.Duration {
+
+	return now().Sub(createdInstant(me.CreatedUTC))
+}
+
+// Karma the account's total karma, falling back to the sum of link and comment karma when reddit does not report a total
+func (me *User) Karma() int {
+
+	if me.TotalKarma != 0 {
+		return me.TotalKarma
+	}
+
+	return me.LinkKarma + me.CommentKarma
+}
+
+// GetUser retrieve a user's account details
+func GetUser(username string) (*User, error) {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/user/%s/about.json", username)
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if object.Type != apiObjectTypeAccount {
+		return nil, errors.New("Provided API Object is not an Account")
+	}
+
+	var result User
+
+	if err := unmarshal(object.Data, &result); err != nil {
+		return nil, err
+	}
+
+	result.CreatedOn = redditTime(result.CreatedUTC)
+
+	return &result, nil
+}
+
+// ClassifyAuthor the AuthorStatus of the author of a post or comment, given the result of looking the author up with GetUser. Returns an empty status when a lookup error does not tell the account's state
+func ClassifyAuthor(author string, user *User, err error) string {
+
+	if author == apiDeletedAuthor {
+		return AuthorStatusDeleted
+	}
+
+	if err != nil {
+
+		if responseErr, ok := err.(*ResponseError); ok && responseErr.StatusCode == http.StatusNotFound {
+			return AuthorStatusShadowbanned
+		}
+
+		return ""
+	}
+
+	if user != nil && user.IsSuspended {
+		return AuthorStatusSuspended
+	}
+
+	return AuthorStatusActive
+}
+
+// authorLookupConcurrency the number of accounts an AuthorEnricher looks up at once by default
+const authorLookupConcurrency = 4
+
+type authorEntry struct {
+	user    *User
+	err     error
+	fetched time.Time
+	done    chan struct{}
+}
+
+// AuthorEnricher attaches the account details and AuthorStatus of each item's author to Posts and Comments. Accounts are fetched once per author and cached for the TTL. Only definitive results are cached: accounts that were found, suspended or not found. Lookups that failed for another reason, such as rate limiting, are retried the next time the author is seen
+type AuthorEnricher struct {
+	TTL time.Duration
+
+	// Concurrency the number of distinct authors EnrichPosts and EnrichComments look up at once. Requests are still spaced out by the guardrails' request rate ceiling
+	Concurrency int
+
+	mutex   sync.Mutex
+	entries map[string]*authorEntry
+}
+
+// NewAuthorEnricher create a new enricher caching account details for ttl. A ttl of zero caches them forever
+func NewAuthorEnricher(ttl time.Duration) *AuthorEnricher {
+
+	return &AuthorEnricher{TTL: ttl, Concurrency: authorLookupConcurrency, entries: make(map[string]*authorEntry)}
+}
+
+// Lookup the account details of a user, from the cache when possible. Concurrent lookups of the same user share a single request
+func (me *AuthorEnricher) Lookup(username string) (*User, error) {
+
+	me.mutex.Lock()
+
+	entry, ok := me.entries[username]
+
+	if ok {
+		select {
+		case <-entry.done:
+			ok = me.TTL <= 0 || now().Sub(entry.fetched) < me.TTL
+		default:
+		}
+	}
+
+	if !ok {
+
+		entry = &authorEntry{done: make(chan struct{})}
+		me.entries[username] = entry
+
+		me.mutex.Unlock()
+
+		me.fetch(username, entry)
+
+		return entry.user, entry.err
+	}
+
+	me.mutex.Unlock()
+
+	<-entry.done
+
+	return entry.user, entry.err
+}
+
+// fetch look up an account for an entry and release the lookups waiting on it. The entry is dropped from the cache unless the result is definitive
+func (me *AuthorEnricher) fetch(username string, entry *authorEntry) {
+
+	entry.user, entry.err = GetUser(username)
+	entry.fetched = now()
+
+	if ClassifyAuthor(username, entry.user, entry.err) == "" {
+
+		me.mutex.Lock()
+
+		if me.entries[username] == entry {
+			delete(me.entries, username)
+		}
+
+		me.mutex.Unlock()
+	}
+
+	close(entry.done)
+}
+
+// lookupAll look up the distinct authors among those provided, Concurrency at a time, so enriching a dataset does not wait on one account at a time
+func (me *AuthorEnricher) lookupAll(authors []string) {
+
+	distinct := make(map[string]bool)
+	queue := make(chan string)
+
+	concurrency := me.Concurrency
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wait sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+
+		wait.Add(1)
+
+		go func() {
+
+			defer wait.Done()
+
+			for author := range queue {
+				me.Lookup(author)
+			}
+		}()
+	}
+
+	for _, author := range authors {
+		if author != "" && author != apiDeletedAuthor && !distinct[author] {
+			distinct[author] = true
+			queue <- author
+		}
+	}
+
+	close(queue)
+
+	wait.Wait()
+}
+
+// EnrichPosts set AuthorAccount and AuthorStatus on each post, fetching the distinct authors concurrently. Returns the lookup error of each author whose status could not be determined
+func (me *AuthorEnricher) EnrichPosts(posts []Post) map[string]error {
+
+	failures := make(map[string]error)
+
+	authors := make([]string, len(posts))
+
+	for i := range posts {
+		authors[i] = posts[i].Author
+	}
+
+	me.lookupAll(authors)
+
+	for i := range posts {
+		posts[i].AuthorAccount, posts[i].AuthorStatus = me.enrich(posts[i].Author, failures)
+	}
+
+	return failures
+}
+
+// EnrichComments set AuthorAccount and AuthorStatus on each comment, fetching the distinct authors concurrently. Returns the lookup error of each author whose status could not be determined
+func (me *AuthorEnricher) EnrichComments(comments []Comment) map[string]error {
+
+	failures := make(map[string]error)
+
+	authors := make([]string, len(comments))
+
+	for i := range comments {
+		authors[i] = comments[i].Author
+	}
+
+	me.lookupAll(authors)
+
+	for i := range comments {
+		comments[i].AuthorAccount, comments[i].AuthorStatus = me.enrich(comments[i].Author, failures)
+	}
+
+	return failures
+}
+
+// Enrich set AuthorAccount and AuthorStatus on a Post or Comment, returning the enriched item. Suitable as a Map stage; lookup failures leave both unset
+func (me *AuthorEnricher) Enrich(item interface{}) interface{} {
+
+	failures := make(map[string]error)
+
+	switch value := item.(type) {
+	case Post:
+		value.AuthorAccount, value.AuthorStatus = me.enrich(value.Author, failures)
+		return value
+	case *Post:
+		value.AuthorAccount, value.AuthorStatus = me.enrich(value.Author, failures)
+	case Comment:
+		value.AuthorAccount, value.AuthorStatus = me.enrich(value.Author, failures)
+		return value
+	case *Comment:
+		value.AuthorAccount, value.AuthorStatus = me.enrich(value.Author, failures)
+	}
+
+	return item
+}
+
+func (me *AuthorEnricher) enrich(author string, failures map[string]error) (*User, string) {
+
+	if author == "" {
+		return nil, ""
+	}
+
+	if author == apiDeletedAuthor {
+		return nil, AuthorStatusDeleted
+	}
+
+	user, err := me.Lookup(author)
+
+	status := ClassifyAuthor(author, user, err)
+
+	if status == "" {
+		failures[author] = err
+	}
+
+	return user, status
+}
+
+// MinAccountAge a filter keeping items whose author's account is active and at least age old. Items must be enriched by an AuthorEnricher first. The filter fails closed: items by deleted, suspended or shadowbanned authors are dropped, as are items whose author could not be looked up; wrap it in KeepUnknownAuthors to keep the latter
+func MinAccountAge(age time.Duration) FilterFunc {
+
+	return authorFilter(func(user *User) bool {
+		return user.AccountAge() >= age
+	})
+}
+
+// MinKarma a filter keeping items whose author's account is active and has at least karma total karma. Items must be enriched by an AuthorEnricher first. The filter fails closed: items by deleted, suspended or shadowbanned authors are dropped, as are items whose author could not be looked up; wrap it in KeepUnknownAuthors to keep the latter
+func MinKarma(karma int) FilterFunc {
+
+	return authorFilter(func(user *User) bool {
+		return user.Karma() >= karma
+	})
+}
+
+// KeepUnknownAuthors a filter keeping items whose author's status is unknown, because they were not enriched or their author's lookup failed, and applying filter to the rest
+func KeepUnknownAuthors(filter FilterFunc) FilterFunc {
+
+	return func(item interface{}) bool {
+
+		if _, status := itemAuthor(item); status == "" {
+			return true
+		}
+
+		return filter(item)
+	}
+}
+
+func authorFilter(fn func(user *User) bool) FilterFunc {
+
+	return func(item interface{}) bool {
+
+		user, status := itemAuthor(item)
+
+		return user != nil && status == AuthorStatusActive && fn(user)
+	}
+}
+
+func itemAuthor(item interface{}) (*User, string) {
+
+	switch value := item.(type) {
+	case Post:
+		return value.AuthorAccount, value.AuthorStatus
+	case *Post:
+		return value.AuthorAccount, value.AuthorStatus
+	case Comment:
+		return value.AuthorAccount, value.AuthorStatus
+	case *Comment:
+		return value.AuthorAccount, value.AuthorStatus
+	}
+
+	return nil, ""
+}
@@ -0,0 +1,39 @@
+package rscraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthorFiltersFailClosed(t *testing.T) {
+
+	veteran := &User{TotalKarma: 500, CreatedUTC: float64(now().Add(-365 * 24 * time.Hour).Unix())}
+	newcomer := &User{TotalKarma: 5, CreatedUTC: float64(now().Add(-time.Hour).Unix())}
+
+	posts := map[string]Post{
+		"veteran":   {AuthorAccount: veteran, AuthorStatus: AuthorStatusActive},
+		"newcomer":  {AuthorAccount: newcomer, AuthorStatus: AuthorStatusActive},
+		"suspended": {AuthorAccount: veteran, AuthorStatus: AuthorStatusSuspended},
+		"shadow":    {AuthorStatus: AuthorStatusShadowbanned},
+		"deleted":   {AuthorStatus: AuthorStatusDeleted},
+		"unknown":   {},
+	}
+
+	filters := map[string]FilterFunc{"age": MinAccountAge(30 * 24 * time.Hour), "karma": MinKarma(100)}
+
+	for name, filter := range filters {
+		for author, post := range posts {
+			if kept := filter(post); kept != (author == "veteran") {
+				t.Errorf("%s filter kept post by %s: %t", name, author, kept)
+			}
+		}
+	}
+
+	lenient := KeepUnknownAuthors(MinKarma(100))
+
+	for author, post := range posts {
+		if kept := lenient(&post); kept != (author == "veteran" || author == "unknown") {
+			t.Errorf("Lenient filter kept post by %s: %t", author, kept)
+		}
+	}
+}
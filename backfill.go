@@ -0,0 +1,128 @@
+package rscraper
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackfillWindow a time range of a subreddit's history retrieved with a single search query
+type BackfillWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Query the cloudsearch query selecting posts created within this window
+func (me BackfillWindow) Query() string {
+
+	return fmt.Sprintf("timestamp:%d..%d", me.Start.Unix(), me.End.Unix()-1)
+}
+
+func (me BackfillWindow) split() (BackfillWindow, BackfillWindow) {
+
+	middle := me.Start.Add(me.End.Sub(me.Start) / 2)
+
+	return BackfillWindow{Start: me.Start, End: middle}, BackfillWindow{Start: middle, End: me.End}
+}
+
+// PlanBackfill divide a time range into consecutive search windows of the provided size, newest first
+func PlanBackfill(start, end time.Time, size time.Duration) []BackfillWindow {
+
+	windows := make([]BackfillWindow, 0)
+
+	if size <= 0 {
+		return windows
+	}
+
+	for windowEnd := end; windowEnd.After(start); windowEnd = windowEnd.Add(-size) {
+
+		windowStart := windowEnd.Add(-size)
+
+		if windowStart.Before(start) {
+			windowStart = start
+		}
+
+		windows = append(windows, BackfillWindow{Start: windowStart, End: windowEnd})
+	}
+
+	return windows
+}
+
+// Backfill retrieve every post in the subreddit created within the provided windows, newest first and without duplicates. Windows whose results hit reddit's listing cap are split in half and searched again until they fit, down to a window of one second. When a one second window is still capped, the posts are returned along with a ListingCapError, as posts beyond the cap could not be reached
+func Backfill(subreddit string, windows []BackfillWindow) ([]Post, error) {
+
+	results := make([][]Post, 0)
+
+	err := backfill(subreddit, windows, func(posts []Post) bool {
+
+		results = append(results, posts)
+		return true
+	})
+
+	if _, capped := err.(*ListingCapError); err != nil && !capped {
+		return nil, err
+	}
+
+	return mergePosts(results...), err
+}
+
+// backfill search each window in turn, passing the posts of each completed window to fn. Stops early when fn returns false. Windows that are capped and cannot be split further are still passed to fn, and the first of them is returned as a ListingCapError once every window has been searched
+func backfill(subreddit string, windows []BackfillWindow, fn func(posts []Post) bool) error {
+
+	var capErr error
+
+	for len(windows) > 0 {
+
+		window := windows[0]
+		windows = windows[1:]
+
+		posts, capped, err := searchAllPosts(subreddit, window.Query())
+
+		if err != nil {
+			return err
+		}
+
+		if capped && window.End.Sub(window.Start) > time.Second {
+
+			older, newer := window.split()
+
+			windows = append([]BackfillWindow{newer, older}, windows...)
+			continue
+		}
+
+		if capped && capErr == nil {
+			capErr = &ListingCapError{Subreddit: subreddit, ListingType: "search " + window.Query(), Count: len(posts)}
+		}
+
+		if !fn(posts) {
+			return capErr
+		}
+	}
+
+	return capErr
+}
+
+func searchAllPosts(subreddit, query string) ([]Post, bool, error) {
+
+	posts := make([]Post, 0)
+
+	after := ""
+
+	listed := 0
+
+	for {
+		page, next, count, err := searchPosts(subreddit, query, after)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		posts = append(posts, page...)
+		listed += count
+
+		if next == "" {
+			return posts, listed >= apiListingCapThreshold, nil
+		}
+
+		after = next
+	}
+}
@@ -0,0 +1,150 @@
+package rscraper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// batchMaxAttempts the number of times a failed chunk of a batch operation is attempted before its items are reported as failed
+	batchMaxAttempts = 3
+
+	// batchRetryDelay the delay before retrying a failed chunk, doubled for every further attempt
+	batchRetryDelay = time.Second
+)
+
+// GetByFullnames retrieve posts and comments by their fullnames (e.g. t3_abc123, t1_def456), in chunks of 100. Chunks that fail with a transient error are retried on their own; the items of chunks that keep failing, and items reddit did not return, are reported in the error map instead of failing the whole batch
+func GetByFullnames(fullnames []string) ([]Post, []Comment, map[string]error) {
+
+	posts := make([]Post, 0)
+	comments := make([]Comment, 0)
+
+	found := make(map[string]bool)
+
+	failures := retryChunks(fullnames, apiMaxIDsPerRequest, func(chunk []string) error {
+
+		chunkPosts, chunkComments, err := getInfo(chunk)
+
+		if err != nil {
+			return err
+		}
+
+		for _, post := range chunkPosts {
+			found[post.Fullname()] = true
+		}
+
+		for _, comment := range chunkComments {
+			found[comment.Fullname()] = true
+		}
+
+		posts = append(posts, chunkPosts...)
+		comments = append(comments, chunkComments...)
+
+		return nil
+	})
+
+	for _, fullname := range fullnames {
+		if _, failed := failures[fullname]; !failed && !found[fullname] {
+			failures[fullname] = fmt.Errorf("Item %s not found", fullname)
+		}
+	}
+
+	return posts, comments, failures
+}
+
+// retryChunks split items into chunks and call fn with each, retrying chunks that failed with a transient error (rate limiting, server errors or no response) with a growing delay. Returns the last error of every item whose chunk never succeeded
+func retryChunks(items []string, size int, fn func(chunk []string) error) map[string]error {
+
+	failures := make(map[string]error)
+
+	for start := 0; start < len(items); start += size {
+
+		end := start + size
+
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunk := items[start:end]
+
+		delay := batchRetryDelay
+
+		var err error
+
+		for attempt := 1; attempt <= batchMaxAttempts; attempt++ {
+
+			if err = fn(chunk); err == nil || !retryable(err) {
+				break
+			}
+
+			if attempt < batchMaxAttempts {
+				<-after(delay)
+				delay *= 2
+			}
+		}
+
+		if err != nil {
+			for _, item := range chunk {
+				failures[item] = err
+			}
+		}
+	}
+
+	return failures
+}
+
+func getInfo(fullnames []string) ([]Post, []Comment, error) {
+
+	posts := make([]Post, 0)
+	comments := make([]Comment, 0)
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = "/api/info.json"
+
+	redditURL.RawQuery = url.Values{"id": {strings.Join(fullnames, ",")}}.Encode()
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := extractListing(object)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, child := range list.Children {
+
+		if post, err := extractPost(&child); err == nil {
+
+			if guardrailsAllowPost(post) {
+				posts = append(posts, *post)
+			}
+
+			continue
+		}
+
+		comment, err := extractComment(&child)
+
+		if err != nil {
+			return nil, nil, errors.New("API Object is not a Post or Comment")
+		}
+
+		if guardrailsAllowComment(comment) {
+			comments = append(comments, *comment)
+		}
+	}
+
+	provenance := newProvenance(redditURL.String(), 0, false)
+
+	setPostProvenance(posts, provenance)
+	setCommentProvenance(comments, provenance)
+
+	return posts, comments, nil
+}
@@ -0,0 +1,69 @@
+package rscraper
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type instantSleeper struct{}
+
+func (me instantSleeper) After(d time.Duration) <-chan time.Time {
+
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+
+	return ch
+}
+
+func TestRetryChunks(t *testing.T) {
+
+	SetSleeper(instantSleeper{})
+	defer SetSleeper(nil)
+
+	items := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name     string
+		errs     map[string][]error
+		failed   []string
+		attempts int
+	}{
+		{"success", nil, nil, 3},
+		{"transient error retried", map[string][]error{"a": {&ResponseError{StatusCode: http.StatusServiceUnavailable}}}, nil, 4},
+		{"rate limit exhausts attempts", map[string][]error{"c": {&ResponseError{StatusCode: http.StatusTooManyRequests}, &ResponseError{StatusCode: http.StatusTooManyRequests}, &ResponseError{StatusCode: http.StatusTooManyRequests}}}, []string{"c", "d"}, 5},
+		{"permanent error not retried", map[string][]error{"e": {&ResponseError{StatusCode: http.StatusForbidden}}}, []string{"e"}, 3},
+		{"other errors not retried", map[string][]error{"a": {errors.New("Bad response")}}, []string{"a", "b"}, 3},
+	}
+
+	for _, test := range tests {
+
+		attempts := 0
+
+		failures := retryChunks(items, 2, func(chunk []string) error {
+
+			attempts++
+
+			if errs := test.errs[chunk[0]]; len(errs) > 0 {
+				test.errs[chunk[0]] = errs[1:]
+				return errs[0]
+			}
+
+			return nil
+		})
+
+		failed := make([]string, 0)
+
+		for _, item := range items {
+			if failures[item] != nil {
+				failed = append(failed, item)
+			}
+		}
+
+		if strings.Join(failed, ",") != strings.Join(test.failed, ",") || attempts != test.attempts {
+			t.Errorf("%s: expected %v to fail after %d attempts, got %v after %d", test.name, test.failed, test.attempts, failed, attempts)
+		}
+	}
+}
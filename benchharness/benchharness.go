@@ -0,0 +1,124 @@
+// Package benchharness a performance regression harness for rscraper: benchmarks over the package's generated listing and thread fixtures, each checked against an allocation budget. Budgets were measured with one Go version on amd64 and are only a guide elsewhere. It is kept apart from the rscraper package so programs importing rscraper do not link in the testing package
+package benchharness
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+// Budget the most a benchmark may allocate per operation before it counts as a regression. Zero fields are not checked
+type Budget struct {
+	AllocsPerOp int64
+	BytesPerOp  int64
+}
+
+// Benchmark a benchmark in the harness
+type Benchmark struct {
+	Name   string
+	Budget Budget
+	Fn     func(b *testing.B)
+}
+
+// Result the outcome of running a benchmark against its allocation budget
+type Result struct {
+	Name   string
+	Result testing.BenchmarkResult
+	Budget Budget
+
+	// OverBudget whether the benchmark allocated more per operation than its budget allows
+	OverBudget bool
+}
+
+// Benchmarks the benchmarks in the harness: decoding a listing page, extracting the comments of a large thread, and building and flattening its comment tree. Budgets leave about a tenth more than the allocations measured when they were set
+func Benchmarks() []Benchmark {
+
+	return []Benchmark{
+		{Name: "ListingDecode", Budget: Budget{AllocsPerOp: 2600, BytesPerOp: 980000}, Fn: benchmarkListingDecode},
+		{Name: "CommentTreeExtract", Budget: Budget{AllocsPerOp: 130000, BytesPerOp: 47500000}, Fn: benchmarkCommentTreeExtract},
+		{Name: "CommentTreeFlatten", Budget: Budget{AllocsPerOp: 4900, BytesPerOp: 2900000}, Fn: benchmarkCommentTreeFlatten},
+	}
+}
+
+// Run run every benchmark in the harness whose name contains filter, checking each against its allocation budget
+func Run(filter string) []Result {
+
+	results := make([]Result, 0)
+
+	for _, benchmark := range Benchmarks() {
+
+		if !strings.Contains(benchmark.Name, filter) {
+			continue
+		}
+
+		result := Result{Name: benchmark.Name, Result: testing.Benchmark(benchmark.Fn), Budget: benchmark.Budget}
+
+		result.OverBudget = (benchmark.Budget.AllocsPerOp > 0 && result.Result.AllocsPerOp() > benchmark.Budget.AllocsPerOp) ||
+			(benchmark.Budget.BytesPerOp > 0 && result.Result.AllocedBytesPerOp() > benchmark.Budget.BytesPerOp)
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (me Result) String() string {
+
+	status := "ok"
+
+	if me.OverBudget {
+		status = "OVER BUDGET"
+	}
+
+	return fmt.Sprintf("%s\t%d\t%d ns/op\t%d allocs/op (budget %d)\t%d B/op (budget %d)\t%s", me.Name, me.Result.N, me.Result.NsPerOp(), me.Result.AllocsPerOp(), me.Budget.AllocsPerOp, me.Result.AllocedBytesPerOp(), me.Budget.BytesPerOp, status)
+}
+
+func benchmarkListingDecode(b *testing.B) {
+
+	data := rscraper.ListingFixture()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := rscraper.DecodeListing(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkCommentTreeExtract(b *testing.B) {
+
+	data := rscraper.ThreadFixture()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := rscraper.DecodeThread(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkCommentTreeFlatten(b *testing.B) {
+
+	_, comments, err := rscraper.DecodeThread(rscraper.ThreadFixture())
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if flattened := rscraper.NewCommentTree(comments).Flatten(); len(flattened) != len(comments) {
+			b.Fatalf("Flattened %d of %d comments", len(flattened), len(comments))
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package benchharness
+
+import (
+	"os"
+	"testing"
+)
+
+func BenchmarkHarness(b *testing.B) {
+
+	for _, benchmark := range Benchmarks() {
+		b.Run(benchmark.Name, benchmark.Fn)
+	}
+}
+
+// TestBenchmarksWithinBudget check the allocation budgets, only when RSCRAPER_BENCH_BUDGETS is set since they were measured on one Go version and architecture
+func TestBenchmarksWithinBudget(t *testing.T) {
+
+	if os.Getenv("RSCRAPER_BENCH_BUDGETS") == "" {
+		t.Skip("Set RSCRAPER_BENCH_BUDGETS to check allocation budgets")
+	}
+
+	for _, result := range Run("") {
+		if result.OverBudget {
+			t.Error(result.String())
+		}
+	}
+}
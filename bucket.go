@@ -0,0 +1,68 @@
+package rscraper
+
+import "time"
+
+const (
+	// BucketDay group items by calendar day
+	BucketDay = "day"
+
+	// BucketWeek group items by week, starting on Monday
+	BucketWeek = "week"
+
+	// BucketMonth group items by calendar month
+	BucketMonth = "month"
+)
+
+// BucketTime the start of the day, week or month containing t, in the provided time zone. A nil location uses UTC
+func BucketTime(t time.Time, bucket string, loc *time.Location) time.Time {
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t = t.In(loc)
+
+	year, month, day := t.Date()
+
+	switch bucket {
+	case BucketWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, loc)
+	case BucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// BucketPosts group posts by the day, week or month they were created in the provided time zone, keyed by the start of each bucket
+func BucketPosts(posts []Post, bucket string, loc *time.Location) map[time.Time][]Post {
+
+	buckets := make(map[time.Time][]Post)
+
+	for _, post := range posts {
+		key := BucketTime(createdInstant(post.CreatedUTC), bucket, loc)
+		buckets[key] = append(buckets[key], post)
+	}
+
+	return buckets
+}
+
+// BucketComments group comments by the day, week or month they were created in the provided time zone, keyed by the start of each bucket
+func BucketComments(comments []Comment, bucket string, loc *time.Location) map[time.Time][]Comment {
+
+	buckets := make(map[time.Time][]Comment)
+
+	for _, comment := range comments {
+		key := BucketTime(createdInstant(comment.CreatedUTC), bucket, loc)
+		buckets[key] = append(buckets[key], comment)
+	}
+
+	return buckets
+}
+
+// createdInstant the instant described by a reddit created_utc timestamp, independent of the local time zone
+func createdInstant(createdUTC float64) time.Time {
+
+	return time.Unix(int64(createdUTC), 0).UTC()
+}
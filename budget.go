@@ -0,0 +1,192 @@
+package rscraper
+
+import (
+	"context"
+	"sync"
+)
+
+// budgetItemOverhead the approximate bytes an item takes in memory beyond the length of its text
+const budgetItemOverhead = 1024
+
+var (
+	budgetMutex    sync.Mutex
+	budgetMaxItems int
+	budgetMaxBytes int64
+	budgetItems    int
+	budgetBytes    int64
+	budgetChanged  = make(chan struct{})
+)
+
+// SetMemoryBudget bound the posts and comments that iterators, StreamPosts, StreamUser and archivers hold in memory ahead of their consumers, in items and in approximate bytes, across the whole package. When the budget is spent, fetching waits until consumers catch up, so a slow sink applies backpressure instead of memory growing without bound. A zero limit is not enforced
+func SetMemoryBudget(maxItems int, maxBytes int64) {
+
+	budgetMutex.Lock()
+	defer budgetMutex.Unlock()
+
+	budgetMaxItems, budgetMaxBytes = maxItems, maxBytes
+
+	budgetNotify()
+}
+
+// MemoryBudgetUsed the items and approximate bytes currently held against the memory budget
+func MemoryBudgetUsed() (int, int64) {
+
+	budgetMutex.Lock()
+	defer budgetMutex.Unlock()
+
+	return budgetItems, budgetBytes
+}
+
+// budgetHold the part of the memory budget held by one fetching goroutine. Not safe for concurrent use
+type budgetHold struct {
+	items int
+	bytes int64
+}
+
+// acquire wait until the budget has room for items and bytes, then hold them. A request larger than the whole budget is granted once nothing else is held, so it cannot wait forever
+func (me *budgetHold) acquire(ctx context.Context, items int, bytes int64) error {
+
+	for {
+		budgetMutex.Lock()
+
+		if budgetFits(items, bytes) {
+
+			budgetItems += items
+			budgetBytes += bytes
+
+			budgetMutex.Unlock()
+
+			me.items += items
+			me.bytes += bytes
+
+			return nil
+		}
+
+		changed := budgetChanged
+
+		budgetMutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release give back part of the hold
+func (me *budgetHold) release(items int, bytes int64) {
+
+	if items > me.items {
+		items = me.items
+	}
+
+	if bytes > me.bytes {
+		bytes = me.bytes
+	}
+
+	me.items -= items
+	me.bytes -= bytes
+
+	budgetMutex.Lock()
+	defer budgetMutex.Unlock()
+
+	budgetItems -= items
+	budgetBytes -= bytes
+
+	budgetNotify()
+}
+
+// releaseAll give back the whole hold
+func (me *budgetHold) releaseAll() {
+
+	me.release(me.items, me.bytes)
+}
+
+// waitForBudget wait until the budget is not spent, without holding any of it. Used before fetching pages the consumer pulls one at a time
+func waitForBudget(ctx context.Context) error {
+
+	var hold budgetHold
+
+	if err := hold.acquire(ctx, 1, 1); err != nil {
+		return err
+	}
+
+	hold.releaseAll()
+
+	return nil
+}
+
+// budgetFits whether items and bytes can be held now. Callers hold the mutex
+func budgetFits(items int, bytes int64) bool {
+
+	if budgetItems == 0 && budgetBytes == 0 {
+		return true
+	}
+
+	if budgetMaxItems > 0 && budgetItems+items > budgetMaxItems {
+		return false
+	}
+
+	return budgetMaxBytes <= 0 || budgetBytes+bytes <= budgetMaxBytes
+}
+
+// budgetNotify wake every goroutine waiting for room. Callers hold the mutex
+func budgetNotify() {
+
+	close(budgetChanged)
+	budgetChanged = make(chan struct{})
+}
+
+// itemSize the approximate bytes an item takes in memory
+func itemSize(item interface{}) int64 {
+
+	switch value := item.(type) {
+	case Post:
+		return postSize(&value)
+	case *Post:
+		return postSize(value)
+	case Comment:
+		return commentSize(&value)
+	case *Comment:
+		return commentSize(value)
+	case UserActivity:
+
+		if value.Post != nil {
+			return postSize(value.Post)
+		}
+
+		if value.Comment != nil {
+			return commentSize(value.Comment)
+		}
+	}
+
+	return budgetItemOverhead
+}
+
+func postSize(post *Post) int64 {
+
+	size := len(post.Title) + len(post.Text) + len(post.TextHTML) + len(post.URL) + len(post.PermaLink) + len(post.Thumbnail) + len(post.AllAwardings)*256
+
+	if post.Preview != nil {
+		size += budgetItemOverhead
+	}
+
+	return int64(size + budgetItemOverhead)
+}
+
+func commentSize(comment *Comment) int64 {
+
+	return int64(len(comment.Body) + len(comment.BodyHTML) + len(comment.Replies) + len(comment.PermaLink) + budgetItemOverhead)
+}
+
+func postsSize(posts []Post) int64 {
+
+	var size int64
+
+	for i := range posts {
+		size += postSize(&posts[i])
+	}
+
+	return size
+}
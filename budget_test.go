@@ -0,0 +1,86 @@
+package rscraper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBudgetBackpressure(t *testing.T) {
+
+	SetMemoryBudget(2, 0)
+	defer SetMemoryBudget(0, 0)
+
+	var first, second budgetHold
+
+	if err := first.acquire(context.Background(), 2, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error, 1)
+
+	go func() {
+		acquired <- second.acquire(context.Background(), 1, 10)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquired beyond the item budget")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	first.release(1, 5)
+
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+
+	first.releaseAll()
+	second.releaseAll()
+
+	if items, bytes := MemoryBudgetUsed(); items != 0 || bytes != 0 {
+		t.Fatalf("Budget still holds %d items and %d bytes", items, bytes)
+	}
+}
+
+func TestMemoryBudgetOversize(t *testing.T) {
+
+	SetMemoryBudget(1, 100)
+	defer SetMemoryBudget(0, 0)
+
+	var hold budgetHold
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := hold.acquire(ctx, 5, 1000); err != nil {
+		t.Fatalf("An oversize request was not granted on an empty budget: %v", err)
+	}
+
+	hold.releaseAll()
+}
+
+func TestPostIteratorStopsWaitingForBudget(t *testing.T) {
+
+	SetMemoryBudget(1, 0)
+	defer SetMemoryBudget(0, 0)
+
+	var hold budgetHold
+
+	if err := hold.acquire(context.Background(), 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	defer hold.releaseAll()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	iterator := NewPostIteratorContext(ctx, "golang", ListingTypeNew, "")
+	defer iterator.Close()
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	if iterator.Next() || iterator.Err() != context.Canceled {
+		t.Fatalf("Expected the iteration to stop with the context, got %v", iterator.Err())
+	}
+}
@@ -0,0 +1,166 @@
+package rscraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PageCache stores raw listing pages keyed by endpoint and cursor, so a crawl can be replayed without fetching the pages again
+type PageCache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, page []byte) error
+}
+
+// PageCacheTimes implemented by page caches that remember when each page was stored, so items read from the cache keep the time they were originally fetched
+type PageCacheTimes interface {
+	StoredAt(key string) (time.Time, bool)
+}
+
+// MemoryPageCache an in-memory PageCache
+type MemoryPageCache struct {
+	mutex sync.RWMutex
+	pages map[string][]byte
+	times map[string]time.Time
+}
+
+// NewMemoryPageCache create a new empty in-memory page cache
+func NewMemoryPageCache() *MemoryPageCache {
+
+	return &MemoryPageCache{pages: make(map[string][]byte), times: make(map[string]time.Time)}
+}
+
+// Get retrieve a cached page
+func (me *MemoryPageCache) Get(key string) ([]byte, bool) {
+
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	page, ok := me.pages[key]
+
+	return page, ok
+}
+
+// Put cache a page
+func (me *MemoryPageCache) Put(key string, page []byte) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.pages[key] = page
+	me.times[key] = now()
+
+	return nil
+}
+
+// StoredAt when a page was cached
+func (me *MemoryPageCache) StoredAt(key string) (time.Time, bool) {
+
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	stored, ok := me.times[key]
+
+	return stored, ok
+}
+
+// DirPageCache a PageCache storing each page as a file in a directory, so cached pages survive a crashed process
+type DirPageCache struct {
+	Dir string
+}
+
+// NewDirPageCache create a new page cache in a directory, creating the directory if it does not exist
+func NewDirPageCache(dir string) (*DirPageCache, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &DirPageCache{Dir: dir}, nil
+}
+
+// Get retrieve a cached page
+func (me *DirPageCache) Get(key string) ([]byte, bool) {
+
+	page, err := ioutil.ReadFile(me.path(key))
+
+	return page, err == nil
+}
+
+// Put cache a page
+func (me *DirPageCache) Put(key string, page []byte) error {
+
+	temp := me.path(key) + ".tmp"
+
+	if err := ioutil.WriteFile(temp, page, 0644); err != nil {
+		return err
+	}
+
+	stored := now()
+
+	if err := os.Chtimes(temp, stored, stored); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, me.path(key))
+}
+
+// StoredAt when a page was cached, from the modification time of its file
+func (me *DirPageCache) StoredAt(key string) (time.Time, bool) {
+
+	info, err := os.Stat(me.path(key))
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return info.ModTime(), true
+}
+
+func (me *DirPageCache) path(key string) string {
+
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(me.Dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// pageCacheKey the cache key of a request: its path and query, which include the pagination cursor, without the host so anonymous and authenticated crawls share pages
+func pageCacheKey(redditURL *url.URL) string {
+
+	return redditURL.Path + "?" + redditURL.Query().Encode()
+}
+
+// getCached retrieve a page from the cache if present, fetching and caching it otherwise. Returns when the page was fetched, which for a cached page is when it was stored if the cache records it, and whether the page came from the cache
+func getCached(cache PageCache, redditURL *url.URL) ([]byte, time.Time, bool, error) {
+
+	if cache == nil {
+		page, err := get(redditURL.String())
+		return page, now(), false, err
+	}
+
+	key := pageCacheKey(redditURL)
+
+	if page, ok := cache.Get(key); ok {
+
+		fetchedAt := time.Time{}
+
+		if times, ok := cache.(PageCacheTimes); ok {
+			fetchedAt, _ = times.StoredAt(key)
+		}
+
+		return page, fetchedAt, true, nil
+	}
+
+	page, err := get(redditURL.String())
+
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	return page, now(), false, cache.Put(key, page)
+}
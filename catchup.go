@@ -0,0 +1,120 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// catchUpMaxDelay the longest a catch up waits before retrying a rate limited page
+const catchUpMaxDelay = time.Minute
+
+// CatchUpPosts retrieve the posts made in one or more subreddits since a checkpoint post, given by fullname, oldest first. The new listing is paged backwards until the checkpoint, or the first post older than it, is reached. Rate limited, failed and unreachable pages are retried with backoff rather than abandoning the catch up. The checkpoint post is looked up first, retried the same way, and the catch up fails if it cannot be found, since without its creation time the catch up could not stop at it once it has left the listing. If it is abandoned anyway, no posts are returned, since the posts reached so far are the newest ones and would leave a gap after the checkpoint. When reddit's listing cap is hit first, the posts that could be reached are returned along with a ListingCapError
+func CatchUpPosts(ctx context.Context, checkpoint string, subreddits ...string) ([]Post, error) {
+
+	subreddit := strings.Join(subreddits, "+")
+
+	checkpoint = postFullname(checkpoint)
+
+	var checkpointPosts []Post
+
+	err := catchUpRetry(ctx, func() (err error) {
+		checkpointPosts, err = getPostsByID([]string{checkpoint})
+		return err
+	})
+
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Unable to look up checkpoint %s: %s", checkpoint, err.Error())
+	}
+
+	if len(checkpointPosts) == 0 || checkpointPosts[0].CreatedUTC <= 0 {
+		return nil, fmt.Errorf("Checkpoint %s not found", checkpoint)
+	}
+
+	checkpointCreated := checkpointPosts[0].CreatedUTC
+
+	missed := make([]Post, 0)
+
+	after := ""
+
+	for index := 0; ; index++ {
+		page, next, err := catchUpPage(ctx, subreddit, after, index)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, post := range page {
+
+			if post.Fullname() == checkpoint || post.CreatedUTC < checkpointCreated {
+				reversePosts(missed)
+				return missed, nil
+			}
+
+			missed = append(missed, post)
+		}
+
+		if next == "" {
+
+			reversePosts(missed)
+
+			if len(missed) >= apiListingCapThreshold {
+				return missed, &ListingCapError{Subreddit: subreddit, ListingType: ListingTypeNew, Count: len(missed)}
+			}
+
+			return missed, nil
+		}
+
+		after = next
+	}
+}
+
+// catchUpPage retrieve a page of the new listing, retrying failed requests as catchUpRetry does
+func catchUpPage(ctx context.Context, subreddit, cursor string, index int) ([]Post, string, error) {
+
+	var page []Post
+	var next string
+
+	err := catchUpRetry(ctx, func() (err error) {
+		page, next, err = GetPostsPage(subreddit, ListingTypeNew, cursor, "", index)
+		return err
+	})
+
+	return page, next, err
+}
+
+// catchUpRetry run fn, retrying rate limited, server and transport errors with backoff until the context is cancelled
+func catchUpRetry(ctx context.Context, fn func() error) error {
+
+	delay := batchRetryDelay
+
+	for {
+		err := fn()
+
+		if err == nil || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-after(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if delay < catchUpMaxDelay {
+			delay *= 2
+		}
+	}
+}
+
+func reversePosts(posts []Post) {
+
+	for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+		posts[i], posts[j] = posts[j], posts[i]
+	}
+}
@@ -0,0 +1,76 @@
+package rscraper
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock a source of the current time. Replace the package clock with SetClock to make time-dependent behaviour deterministic in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// Sleeper waits for a duration to elapse. Replace the package sleeper with SetSleeper to control polling intervals in tests
+type Sleeper interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (me systemClock) Now() time.Time {
+
+	return time.Now()
+}
+
+func (me systemClock) After(d time.Duration) <-chan time.Time {
+
+	return time.After(d)
+}
+
+var (
+	clockMutex sync.RWMutex
+	clock      Clock   = systemClock{}
+	sleeper    Sleeper = systemClock{}
+)
+
+// SetClock replace the clock used by the package. Passing nil restores the system clock
+func SetClock(c Clock) {
+
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+
+	if c == nil {
+		c = systemClock{}
+	}
+
+	clock = c
+}
+
+// SetSleeper replace the sleeper used by the package. Passing nil restores the system sleeper
+func SetSleeper(s Sleeper) {
+
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+
+	if s == nil {
+		s = systemClock{}
+	}
+
+	sleeper = s
+}
+
+func now() time.Time {
+
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+
+	return clock.Now()
+}
+
+func after(d time.Duration) <-chan time.Time {
+
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+
+	return sleeper.After(d)
+}
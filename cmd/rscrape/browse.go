@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+// browseWidth the width comment text is wrapped to, before indentation
+const browseWidth = 80
+
+// browse list a subreddit's posts a page at a time and show the comment tree of a selected post, full screen when run in a terminal and with a line based prompt otherwise
+func browse(args []string) error {
+
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+
+	plain := flags.Bool("plain", false, "use the line based prompt even in a terminal")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("Expected a single subreddit, e.g. r/golang")
+	}
+
+	subreddit := strings.TrimPrefix(strings.TrimPrefix(flags.Arg(0), "/"), "r/")
+
+	if !*plain {
+		if term, err := openTerminal(); err == nil {
+
+			defer term.Close()
+
+			return browseTerminal(term, subreddit)
+		}
+	}
+
+	return browsePrompt(subreddit)
+}
+
+// browsePrompt browse a subreddit with a line based prompt, for when standard input or output is not a terminal
+func browsePrompt(subreddit string) error {
+
+	input := bufio.NewScanner(os.Stdin)
+	output := bufio.NewWriter(os.Stdout)
+
+	defer output.Flush()
+
+	after := ""
+	history := make([]string, 0)
+
+	for {
+		posts, next, err := rscraper.GetPostsPage(subreddit, rscraper.ListingTypeHot, after, "", len(history))
+
+		if err != nil {
+			return err
+		}
+
+		listPosts(output, subreddit, posts)
+
+	prompt:
+		for {
+			fmt.Fprint(output, "\n[number] open post, [n]ext page, [p]revious page, [q]uit: ")
+			output.Flush()
+
+			if !input.Scan() {
+				return input.Err()
+			}
+
+			choice := strings.TrimSpace(input.Text())
+
+			switch choice {
+			case "q":
+				return nil
+			case "n":
+
+				if next == "" {
+					fmt.Fprintln(output, "No more posts")
+					continue
+				}
+
+				history = append(history, after)
+				after = next
+
+				break prompt
+			case "p":
+
+				if len(history) == 0 {
+					fmt.Fprintln(output, "Already on the first page")
+					continue
+				}
+
+				after = history[len(history)-1]
+				history = history[:len(history)-1]
+
+				break prompt
+			case "":
+				listPosts(output, subreddit, posts)
+				continue
+			}
+
+			index, err := strconv.Atoi(choice)
+
+			if err != nil || index < 1 || index > len(posts) {
+				fmt.Fprintf(output, "Enter a number between 1 and %d\n", len(posts))
+				continue
+			}
+
+			thread, err := rscraper.GetThread(subreddit, posts[index-1].ID, "")
+
+			if err != nil {
+				fmt.Fprintf(output, "Could not load thread: %s\n", err.Error())
+				continue
+			}
+
+			showThread(output, thread, browseWidth)
+		}
+	}
+}
+
+func listPosts(w io.Writer, subreddit string, posts []rscraper.Post) {
+
+	fmt.Fprintf(w, "\nr/%s\n\n", subreddit)
+
+	for i, post := range posts {
+
+		flair := ""
+
+		if post.LinkFlairText != "" {
+			flair = " [" + post.LinkFlairText + "]"
+		}
+
+		fmt.Fprintf(w, "%3d. %s%s\n     %d points, %d comments, by %s\n", i+1, post.Title, flair, post.Score, post.NumComments, post.Author)
+	}
+}
+
+// showThread write a thread with its comment tree, wrapping text to width characters before indentation
+func showThread(w io.Writer, thread *rscraper.Thread, width int) {
+
+	post := thread.Post
+
+	fmt.Fprintf(w, "\n%s\n%d points, by %s\n", post.Title, post.Score, post.Author)
+
+	if post.IsSelf {
+		for _, line := range wrapText(post.Text, width) {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	} else {
+		fmt.Fprintf(w, "  %s\n", post.URL)
+	}
+
+	fmt.Fprintln(w)
+
+	thread.Comments.Walk(func(node *rscraper.CommentNode, depth int) {
+
+		indent := strings.Repeat("  ", depth) + "| "
+
+		fmt.Fprintf(w, "%s%s, %d points\n", indent, node.Comment.Author, node.Comment.Score)
+
+		for _, line := range wrapText(node.Comment.Body, width) {
+			fmt.Fprintf(w, "%s  %s\n", indent, line)
+		}
+
+		fmt.Fprintln(w, strings.TrimRight(indent, " "))
+	})
+
+	if len(thread.More) > 0 {
+		fmt.Fprintf(w, "(%d more comments not loaded)\n", len(thread.More))
+	}
+}
+
+// wrapText split text into lines of at most width characters, breaking at spaces and keeping existing line breaks
+func wrapText(text string, width int) []string {
+
+	lines := make([]string, 0)
+
+	for _, paragraph := range strings.Split(text, "\n") {
+
+		line := ""
+
+		for _, word := range strings.Fields(paragraph) {
+
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+
+			if line != "" {
+				line += " "
+			}
+
+			line += word
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
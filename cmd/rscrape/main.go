@@ -0,0 +1,65 @@
+// Command rscrape is a command line interface to the rscraper library
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands = map[string]command{
+	"browse": {usage: "browse [-plain] r/SUBREDDIT", description: "interactively list posts and read their comments", run: browse},
+	"schema": {usage: "schema [TYPE]", description: "print the JSON Schema of an exported type, or list the types", run: schema},
+	"serve":  {usage: "serve [-addr ADDR] [-token TOKEN] [-rpm N]", description: "serve the HTTP API", run: serve},
+}
+
+func main() {
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "rscrape %s: %s\n", os.Args[1], rscraper.Redact(err.Error()))
+		os.Exit(1)
+	}
+}
+
+func usage() {
+
+	fmt.Fprintln(os.Stderr, "usage: rscrape COMMAND [ARGUMENTS]")
+	fmt.Fprintln(os.Stderr)
+
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	writer := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+
+	for _, name := range names {
+		fmt.Fprintf(writer, "  %s\t%s\n", commands[name].usage, commands[name].description)
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+// schema print the JSON Schema of an exported type, or the names of the types with schemas
+func schema(args []string) error {
+
+	if len(args) == 0 {
+		fmt.Println(strings.Join(rscraper.SchemaNames(), "\n"))
+		return nil
+	}
+
+	if len(args) > 1 {
+		return errors.New("Expected a single type name")
+	}
+
+	data, err := rscraper.JSONSchema(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+
+	return err
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+// serveRequestsPerMinute the default request rate ceiling of the server, within the limit reddit allows a single client
+const serveRequestsPerMinute = 60
+
+// serve run the HTTP API server until the process is stopped
+func serve(args []string) error {
+
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	token := flags.String("token", os.Getenv("RSCRAPE_TOKEN"), "bearer token clients must send, defaults to $RSCRAPE_TOKEN")
+	rpm := flags.Int("rpm", serveRequestsPerMinute, "the most requests per minute made to reddit on behalf of all clients, 0 for no limit")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		return errors.New("A token is required, set -token or RSCRAPE_TOKEN")
+	}
+
+	rscraper.SetGuardrails(rscraper.Guardrails{MaxRequestsPerMinute: *rpm})
+
+	server := rscraper.NewServer(*token)
+
+	defer server.Close()
+
+	fmt.Fprintf(os.Stderr, "listening on %s\n", *addr)
+
+	return http.ListenAndServe(*addr, server)
+}
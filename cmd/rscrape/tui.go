@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+const (
+	keyUp       = "up"
+	keyDown     = "down"
+	keyLeft     = "left"
+	keyRight    = "right"
+	keyPageUp   = "pgup"
+	keyPageDown = "pgdn"
+	keyHome     = "home"
+	keyEnd      = "end"
+	keyEnter    = "enter"
+	keyBack     = "backspace"
+)
+
+// terminal a full screen terminal in raw mode, drawn with ANSI escape sequences
+type terminal struct {
+	in    *bufio.Reader
+	out   *bufio.Writer
+	saved string
+	rows  int
+	cols  int
+}
+
+// openTerminal switch the controlling terminal to raw mode and the alternate screen. Fails when standard input or output is not a terminal, or stty is not available
+func openTerminal() (*terminal, error) {
+
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, errors.New("Standard output is not a terminal")
+	}
+
+	saved, err := stty("-g")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	result := &terminal{in: bufio.NewReader(os.Stdin), out: bufio.NewWriter(os.Stdout), saved: strings.TrimSpace(saved)}
+
+	result.resize()
+
+	result.out.WriteString("\x1b[?1049h\x1b[?25l")
+	result.out.Flush()
+
+	return result, nil
+}
+
+// Close restore the terminal to the state it was in before it was opened
+func (me *terminal) Close() error {
+
+	me.out.WriteString("\x1b[?25h\x1b[?1049l")
+	me.out.Flush()
+
+	_, err := stty(me.saved)
+
+	return err
+}
+
+// resize read the size of the terminal, keeping the previous size if it cannot be read
+func (me *terminal) resize() {
+
+	me.rows, me.cols = 24, 80
+
+	size, err := stty("size")
+
+	if err != nil {
+		return
+	}
+
+	fields := strings.Fields(size)
+
+	if len(fields) != 2 {
+		return
+	}
+
+	if rows, err := strconv.Atoi(fields[0]); err == nil && rows > 2 {
+		me.rows = rows
+	}
+
+	if cols, err := strconv.Atoi(fields[1]); err == nil && cols > 10 {
+		me.cols = cols
+	}
+}
+
+// draw replace the screen with lines, clipped to the terminal, and a status line at the bottom
+func (me *terminal) draw(lines []string, status string) {
+
+	me.out.WriteString("\x1b[H\x1b[2J")
+
+	for i := 0; i < me.rows-1 && i < len(lines); i++ {
+		me.out.WriteString(clip(lines[i], me.cols) + "\r\n")
+	}
+
+	fmt.Fprintf(me.out, "\x1b[%d;1H\x1b[7m%s\x1b[0m", me.rows, clip(status, me.cols))
+	me.out.Flush()
+}
+
+// readKey read a key press, naming the arrow, paging and editing keys
+func (me *terminal) readKey() (string, error) {
+
+	r, _, err := me.in.ReadRune()
+
+	if err != nil {
+		return "", err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 127, 8:
+		return keyBack, nil
+	case 3:
+		return "q", nil
+	case 27:
+	default:
+		return string(r), nil
+	}
+
+	if next, err := me.in.ReadByte(); err != nil || (next != '[' && next != 'O') {
+		return "", err
+	}
+
+	sequence := ""
+
+	for {
+		b, err := me.in.ReadByte()
+
+		if err != nil {
+			return "", err
+		}
+
+		sequence += string(b)
+
+		if b >= 'A' && b <= 'Z' || b == '~' {
+			break
+		}
+	}
+
+	switch sequence {
+	case "A":
+		return keyUp, nil
+	case "B":
+		return keyDown, nil
+	case "C":
+		return keyRight, nil
+	case "D":
+		return keyLeft, nil
+	case "5~":
+		return keyPageUp, nil
+	case "6~":
+		return keyPageDown, nil
+	case "H", "1~":
+		return keyHome, nil
+	case "F", "4~":
+		return keyEnd, nil
+	}
+
+	return "", nil
+}
+
+// browseTerminal browse a subreddit full screen: the arrow keys select a post and scroll its thread, enter opens the selected post, left or backspace returns to the listing, n and p page through it and q quits
+func browseTerminal(term *terminal, subreddit string) error {
+
+	after := ""
+	history := make([]string, 0)
+
+	for {
+		term.draw(nil, "Loading r/"+subreddit+"...")
+
+		posts, next, err := rscraper.GetPostsPage(subreddit, rscraper.ListingTypeHot, after, "", len(history))
+
+		if err != nil {
+			return err
+		}
+
+		selected, top := 0, 0
+		message := ""
+
+	listing:
+		for {
+			visible := (term.rows - 2) / 2
+
+			if selected < top {
+				top = selected
+			} else if visible > 0 && selected >= top+visible {
+				top = selected - visible + 1
+			}
+
+			lines := []string{fmt.Sprintf("r/%s, page %d", subreddit, len(history)+1), ""}
+
+			for i := top; i < len(posts) && i < top+visible; i++ {
+
+				marker := "  "
+
+				if i == selected {
+					marker = "> "
+				}
+
+				post := posts[i]
+
+				flair := ""
+
+				if post.LinkFlairText != "" {
+					flair = " [" + post.LinkFlairText + "]"
+				}
+
+				lines = append(lines, fmt.Sprintf("%s%3d. %s%s", marker, i+1, post.Title, flair))
+				lines = append(lines, fmt.Sprintf("       %d points, %d comments, by %s", post.Score, post.NumComments, post.Author))
+			}
+
+			status := "up/down select  enter open  n/p next/previous page  q quit"
+
+			if message != "" {
+				status = message
+				message = ""
+			}
+
+			term.draw(lines, status)
+
+			key, err := term.readKey()
+
+			if err != nil {
+				return err
+			}
+
+			switch key {
+			case "q":
+				return nil
+			case keyUp, "k":
+				if selected > 0 {
+					selected--
+				}
+			case keyDown, "j":
+				if selected < len(posts)-1 {
+					selected++
+				}
+			case keyHome:
+				selected = 0
+			case keyEnd:
+				selected = len(posts) - 1
+			case "n", keyRight:
+
+				if next == "" {
+					message = "No more posts"
+					continue
+				}
+
+				history = append(history, after)
+				after = next
+
+				break listing
+			case "p":
+
+				if len(history) == 0 {
+					message = "Already on the first page"
+					continue
+				}
+
+				after = history[len(history)-1]
+				history = history[:len(history)-1]
+
+				break listing
+			case keyEnter:
+
+				if len(posts) == 0 {
+					continue
+				}
+
+				term.draw(lines, "Loading thread...")
+
+				thread, err := rscraper.GetThread(subreddit, posts[selected].ID, "")
+
+				if err != nil {
+					message = "Could not load thread: " + err.Error()
+					continue
+				}
+
+				if quit, err := viewThread(term, thread); err != nil || quit {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// viewThread show a thread until the user returns to the listing, reporting whether they quit instead
+func viewThread(term *terminal, thread *rscraper.Thread) (bool, error) {
+
+	width := term.cols - 4
+
+	if width > browseWidth {
+		width = browseWidth
+	}
+
+	var buf bytes.Buffer
+
+	showThread(&buf, thread, width)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	top := 0
+
+	for {
+		page := term.rows - 1
+
+		last := len(lines) - page
+
+		if last < 0 {
+			last = 0
+		}
+
+		if top > last {
+			top = last
+		}
+
+		if top < 0 {
+			top = 0
+		}
+
+		end := top + page
+
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		term.draw(lines[top:], fmt.Sprintf("lines %d-%d of %d  up/down scroll  left back  q quit", top+1, end, len(lines)))
+
+		key, err := term.readKey()
+
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case "q":
+			return true, nil
+		case keyLeft, keyBack, "b", "h":
+			return false, nil
+		case keyUp, "k":
+			top--
+		case keyDown, "j", keyEnter:
+			top++
+		case keyPageUp:
+			top -= page
+		case keyPageDown, " ":
+			top += page
+		case keyHome:
+			top = 0
+		case keyEnd:
+			top = last
+		}
+	}
+}
+
+// clip cut a line to at most width characters
+func clip(line string, width int) string {
+
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	return string([]rune(line)[:width])
+}
+
+func stty(args ...string) (string, error) {
+
+	cmd := exec.Command("stty", args...)
+
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+
+	return string(out), err
+}
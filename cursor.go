@@ -0,0 +1,121 @@
+package rscraper
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	cursorMutex sync.Mutex
+	cursorPath  string
+)
+
+// SetCursorPath set the JSON file named cursors are kept in. Passing an empty path restores the default, cursors.json in an rscraper directory under the user's configuration directory
+func SetCursorPath(path string) {
+
+	cursorMutex.Lock()
+	defer cursorMutex.Unlock()
+
+	cursorPath = path
+}
+
+// SaveCursor save a named cursor, such as the after value of a listing, so a crawl can resume from it in a later run. Saving an empty cursor deletes it
+func SaveCursor(name, cursor string) error {
+
+	cursorMutex.Lock()
+	defer cursorMutex.Unlock()
+
+	path, err := currentCursorPath()
+
+	if err != nil {
+		return err
+	}
+
+	cursors, err := readCursors(path)
+
+	if err != nil {
+		return err
+	}
+
+	if cursor == "" {
+		delete(cursors, name)
+	} else {
+		cursors[name] = cursor
+	}
+
+	data, err := json.MarshalIndent(cursors, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// LoadCursor load a named cursor saved by SaveCursor. Returns an empty cursor when none was saved under the name
+func LoadCursor(name string) (string, error) {
+
+	cursorMutex.Lock()
+	defer cursorMutex.Unlock()
+
+	path, err := currentCursorPath()
+
+	if err != nil {
+		return "", err
+	}
+
+	cursors, err := readCursors(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	return cursors[name], nil
+}
+
+func currentCursorPath() (string, error) {
+
+	if cursorPath != "" {
+		return cursorPath, nil
+	}
+
+	dir, err := os.UserConfigDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "rscraper", "cursors.json"), nil
+}
+
+func readCursors(path string) (map[string]string, error) {
+
+	cursors := make(map[string]string)
+
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, err
+	}
+
+	return cursors, nil
+}
@@ -0,0 +1,45 @@
+package rscraper
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// JSONDecoder decodes reddit API responses. Any decoder compatible with encoding/json, such as jsoniter.ConfigCompatibleWithStandardLibrary or sonic.ConfigStd, satisfies this interface. The package depends on neither; benchmarks against jsoniter are built with the jsoniter build tag
+type JSONDecoder interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type standardDecoder struct{}
+
+func (me standardDecoder) Unmarshal(data []byte, v interface{}) error {
+
+	return json.Unmarshal(data, v)
+}
+
+var (
+	decoderMutex sync.RWMutex
+	decoder      JSONDecoder = standardDecoder{}
+)
+
+// SetJSONDecoder replace the decoder used to parse API responses. Passing nil restores encoding/json
+func SetJSONDecoder(d JSONDecoder) {
+
+	decoderMutex.Lock()
+	defer decoderMutex.Unlock()
+
+	if d == nil {
+		d = standardDecoder{}
+	}
+
+	decoder = d
+}
+
+func unmarshal(data []byte, v interface{}) error {
+
+	decoderMutex.RLock()
+	current := decoder
+	decoderMutex.RUnlock()
+
+	return current.Unmarshal(data, v)
+}
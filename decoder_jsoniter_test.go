@@ -0,0 +1,53 @@
+//go:build jsoniter
+// +build jsoniter
+
+package rscraper
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Benchmarks against jsoniter, run with: go test -tags jsoniter -run xxx -bench Jsoniter
+
+func BenchmarkUnmarshalJsoniter(b *testing.B) {
+
+	benchmarkDecoder(b, jsoniter.ConfigCompatibleWithStandardLibrary)
+}
+
+func BenchmarkUnmarshalListingJsoniter(b *testing.B) {
+
+	data := ListingFixture()
+
+	SetJSONDecoder(jsoniter.ConfigCompatibleWithStandardLibrary)
+	defer SetJSONDecoder(nil)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := parsePostListing(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestJsoniterDecodesThreads(t *testing.T) {
+
+	_, expected, err := DecodeThread(ThreadFixture())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetJSONDecoder(jsoniter.ConfigCompatibleWithStandardLibrary)
+	defer SetJSONDecoder(nil)
+
+	_, comments, err := DecodeThread(ThreadFixture())
+
+	if err != nil || len(comments) != len(expected) {
+		t.Fatalf("Expected %d comments as with encoding/json, got %d, %v", len(expected), len(comments), err)
+	}
+}
@@ -0,0 +1,87 @@
+package rscraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// streamDecoder a JSONDecoder reading through json.Decoder instead of json.Unmarshal, the shape most alternative decoders take
+type streamDecoder struct{}
+
+func (me streamDecoder) Unmarshal(data []byte, v interface{}) error {
+
+	return json.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// benchmarkDecoder decode the listing fixture into the package's listing types with d. To compare another decoder, such as jsoniter.ConfigCompatibleWithStandardLibrary, add a benchmark passing it here
+func benchmarkDecoder(b *testing.B, d JSONDecoder) {
+
+	data := ListingFixture()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+
+		var object apiObject
+
+		if err := d.Unmarshal(data, &object); err != nil {
+			b.Fatal(err)
+		}
+
+		var list listing
+
+		if err := d.Unmarshal(object.Data, &list); err != nil {
+			b.Fatal(err)
+		}
+
+		for _, child := range list.Children {
+
+			var post Post
+
+			if err := d.Unmarshal(child.Data, &post); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkUnmarshalStandard(b *testing.B) {
+
+	benchmarkDecoder(b, standardDecoder{})
+}
+
+func BenchmarkUnmarshalStream(b *testing.B) {
+
+	benchmarkDecoder(b, streamDecoder{})
+}
+
+// BenchmarkUnmarshalListing parse the listing fixture with alternative decoders. Parsing it with the standard decoder is the ListingDecode benchmark of the benchharness package
+func BenchmarkUnmarshalListing(b *testing.B) {
+
+	data := ListingFixture()
+
+	for _, d := range []struct {
+		name    string
+		decoder JSONDecoder
+	}{{"stream", streamDecoder{}}} {
+
+		b.Run(d.name, func(b *testing.B) {
+
+			SetJSONDecoder(d.decoder)
+			defer SetJSONDecoder(nil)
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := parsePostListing(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
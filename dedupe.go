@@ -0,0 +1,125 @@
+package rscraper
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupeStore remembers keys that have already been seen, such as post fullnames or canonical URLs
+type DedupeStore interface {
+
+	// Add record value under key. If key was already recorded, the previously recorded value is returned along with true and the store is left unchanged
+	Add(key, value string) (string, bool, error)
+}
+
+// DedupeLookup a DedupeStore that can also check for a key without recording it
+type DedupeLookup interface {
+
+	// Lookup the value recorded under key, if any
+	Lookup(key string) (string, bool, error)
+}
+
+// DedupeRange a DedupeStore that can also list the keys it has recorded
+type DedupeRange interface {
+
+	// Range call fn with every recorded key starting with prefix and its value
+	Range(prefix string, fn func(key, value string) error) error
+}
+
+type dedupeEntry struct {
+	value string
+	added time.Time
+}
+
+// MemoryDedupeStore an in-memory DedupeStore whose entries expire after a retention period
+type MemoryDedupeStore struct {
+	Retention time.Duration
+	mutex     sync.Mutex
+	entries   map[string]dedupeEntry
+	lastPrune time.Time
+}
+
+// NewMemoryDedupeStore create a new in-memory dedupe store. Entries older than retention are forgotten. A retention of zero keeps entries forever
+func NewMemoryDedupeStore(retention time.Duration) *MemoryDedupeStore {
+
+	return &MemoryDedupeStore{Retention: retention, entries: make(map[string]dedupeEntry)}
+}
+
+// Add record value under key, returning the existing value if key was recorded within the retention period
+func (me *MemoryDedupeStore) Add(key, value string) (string, bool, error) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	current := now()
+
+	me.prune(current)
+
+	if entry, ok := me.entries[key]; ok && !me.expired(entry, current) {
+		return entry.value, true, nil
+	}
+
+	me.entries[key] = dedupeEntry{value: value, added: current}
+
+	return "", false, nil
+}
+
+// Lookup the value recorded under key within the retention period, without recording anything
+func (me *MemoryDedupeStore) Lookup(key string) (string, bool, error) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if entry, ok := me.entries[key]; ok && !me.expired(entry, now()) {
+		return entry.value, true, nil
+	}
+
+	return "", false, nil
+}
+
+// Range call fn with every key starting with prefix recorded within the retention period and its value
+func (me *MemoryDedupeStore) Range(prefix string, fn func(key, value string) error) error {
+
+	me.mutex.Lock()
+
+	current := now()
+
+	entries := make(map[string]string)
+
+	for key, entry := range me.entries {
+		if strings.HasPrefix(key, prefix) && !me.expired(entry, current) {
+			entries[key] = entry.value
+		}
+	}
+
+	me.mutex.Unlock()
+
+	for key, value := range entries {
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (me *MemoryDedupeStore) expired(entry dedupeEntry, current time.Time) bool {
+
+	return me.Retention > 0 && current.Sub(entry.added) > me.Retention
+}
+
+func (me *MemoryDedupeStore) prune(current time.Time) {
+
+	if me.Retention <= 0 || current.Sub(me.lastPrune) < me.Retention {
+		return
+	}
+
+	for key, entry := range me.entries {
+		if me.expired(entry, current) {
+			delete(me.entries, key)
+		}
+	}
+
+	me.lastPrune = current
+}
@@ -0,0 +1,21 @@
+// Package rscraper scrapes posts, comments and subreddit metadata from reddit's public JSON API.
+//
+// The package is organized by area, one or a few files each:
+//
+// Client: requests, authentication and request policy. SetAccessToken and GetAccessToken (auth.go), SetGuardrails (guardrails.go), SetSubredditPoliteness (politeness.go), SetJSONDecoder (decoder.go), SetClock and SetSleeper (clock.go), SetUTC (times.go), SetMemoryBudget (budget.go), SetWARCRecorder (warc.go), SetRedactedParams, SetURLRedactor and RedactSecret (redact.go) and RegisterEndpoint (endpoint.go).
+//
+// Types: Subreddit, Post and Comment (rscrape.go), Thread and CommentTree (thread.go), Provenance (provenance.go) and the errors in status.go and iterator.go.
+//
+// Retrieval: GetSubreddit, GetPosts, GetComments and GetPostWithComments (rscrape.go), GetThread and GetThreads (thread.go), PostIterator (iterator.go), SearchPosts and Backfill (search.go, backfill.go), Archiver (archive.go), GetByFullnames (batch.go), Ancestors and Subtree (ancestry.go), and the user, moderation and inbox functions in user.go, modlog.go, modqueue.go and inbox.go.
+//
+// Streams: StreamPosts (stream.go), Watchlist (watch.go), StreamUser, StreamModLog, StreamModQueue and StreamInbox, the trackers and monitors in tracker.go, growth.go, repost.go and alert.go, and the pipeline stages in pipeline.go.
+//
+// Sinks and storage: Sink, JSONSink and BatchSink (sink.go), TemplateSink (template.go), Store, MemoryStore, FileStore and SQLStore (store.go, filestore.go, sqlstore.go), QueryStore (query.go), CopyStore and ExportStore (migrate.go), Merger (merge.go), and the readers for dumps and data exports (dump.go, gdpr.go).
+//
+// Rendering: RenderMarkdown and RenderHTML (render.go) and ExportSite (site.go).
+//
+// Media: DownloadSubredditAssets, ResolvePostMedia and DownloadPostMedia (media.go) and MediaDownloader (mediadownload.go).
+//
+// The package can also be run as a sidecar: Server (server.go) serves it over HTTP, and the server/grpc package, built with the grpc build tag, serves the Scraper service of proto/scraper_service.proto over gRPC.
+
+package rscraper
@@ -0,0 +1,150 @@
+package rscraper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/littlehawk93/rscraper/internal/zstd"
+)
+
+type dumpProbe struct {
+	Title      *string     `json:"title"`
+	LinkID     string      `json:"link_id"`
+	CreatedUTC json.Number `json:"created_utc"`
+}
+
+// ReadDump read a newline delimited JSON dump of reddit submissions or comments, such as the monthly Pushshift RS_ and RC_ files, calling fn with each line decoded into a Post or Comment. The dumps are distributed zstd compressed; open them with OpenZstdDump, or wrap the file in another zstd decoder, before passing them in. Lines that are neither are skipped
+func ReadDump(r io.Reader, fn func(item interface{}) error) error {
+
+	reader := bufio.NewReaderSize(r, 1<<20)
+
+	for line := 1; ; line++ {
+
+		data, err := reader.ReadBytes('\n')
+
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+
+			item, decodeErr := decodeDumpLine(data)
+
+			if decodeErr != nil {
+				return fmt.Errorf("Line %d: %s", line, decodeErr.Error())
+			}
+
+			if item != nil {
+				if err := fn(item); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// OpenZstdDump open a zstd compressed dump for ReadDump or ImportDump, decompressing it as it is read. The long match windows the Pushshift dumps are compressed with, up to the 2GB of zstd --long=31, are allowed for; window memory is only allocated as the window fills. A corrupt or truncated file is reported by Read
+func OpenZstdDump(path string) (io.ReadCloser, error) {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdReader{Reader: zstd.NewReader(bufio.NewReaderSize(file, 1<<20)), file: file}, nil
+}
+
+// zstdReader a decompressing reader over a dump file, closing the file when closed
+type zstdReader struct {
+	*zstd.Reader
+	file *os.File
+}
+
+func (me *zstdReader) Close() error {
+
+	return me.file.Close()
+}
+
+// ImportDump write every post and comment in a dump read by ReadDump to a sink, and flush it. Returns the number of items imported
+func ImportDump(r io.Reader, sink Sink) (int, error) {
+
+	imported := 0
+
+	err := ReadDump(r, func(item interface{}) error {
+
+		if err := sink.Write(item); err != nil {
+			return err
+		}
+
+		imported++
+
+		return nil
+	})
+
+	if err != nil {
+		return imported, err
+	}
+
+	return imported, sink.Flush()
+}
+
+// decodeDumpLine decode a dump line into a Post or Comment. Older dumps record created_utc as a string, which is accepted as well
+func decodeDumpLine(data []byte) (interface{}, error) {
+
+	var probe dumpProbe
+
+	if err := unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	created, _ := probe.CreatedUTC.Float64()
+
+	object := &apiObject{Data: data}
+
+	switch {
+	case probe.LinkID != "":
+
+		var comment Comment
+
+		if err := unmarshal(data, &comment); err != nil && !isDumpTimestampError(err) {
+			return nil, err
+		}
+
+		comment.CreatedUTC = created
+		comment.CreatedOn = redditTime(created)
+		comment.EditedOn, comment.BannedOn = extractModifiedTimes(object)
+
+		return comment, nil
+	case probe.Title != nil:
+
+		var post Post
+
+		if err := unmarshal(data, &post); err != nil && !isDumpTimestampError(err) {
+			return nil, err
+		}
+
+		post.CreatedUTC = created
+		post.CreatedOn = redditTime(created)
+		post.EditedOn, post.BannedOn = extractModifiedTimes(object)
+
+		return post, nil
+	}
+
+	return nil, nil
+}
+
+func isDumpTimestampError(err error) bool {
+
+	typeErr, ok := err.(*json.UnmarshalTypeError)
+
+	return ok && typeErr.Field == "created_utc"
+}
@@ -0,0 +1,134 @@
+package rscraper
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testDump the content of testdata/RS_test.ndjson.zst
+const testDump = `{"id":"abc123","title":"A submission","subreddit":"golang","created_utc":"1577836800"}
+{"id":"def456","link_id":"t3_abc123","body":"A comment","subreddit":"golang","created_utc":1577836900}
+{"id":"ghi789","kind":"neither"}
+`
+
+func TestOpenZstdDump(t *testing.T) {
+
+	dump, err := OpenZstdDump(filepath.Join("testdata", "RS_test.ndjson.zst"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dump.Close()
+
+	sink := &failingSink{}
+
+	imported, err := ImportDump(dump, sink)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported != 2 {
+		t.Fatalf("Expected 2 items imported, got %d", imported)
+	}
+
+	if post, ok := sink.written[0].(Post); !ok || post.ID != "abc123" || post.CreatedOn.IsZero() {
+		t.Fatalf("Unexpected first item: %#v", sink.written[0])
+	}
+
+	if comment, ok := sink.written[1].(Comment); !ok || comment.PostID != "t3_abc123" {
+		t.Fatalf("Unexpected second item: %#v", sink.written[1])
+	}
+
+	again, err := OpenZstdDump(filepath.Join("testdata", "RS_test.ndjson.zst"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+
+	if data, err := ioutil.ReadAll(again); err != nil || string(data) != testDump {
+		t.Fatalf("Expected the fixture to decompress to the test dump, got %q, %v", data, err)
+	}
+
+	dir, err := ioutil.TempDir("", "rscraper")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bad.zst")
+
+	if err := ioutil.WriteFile(path, []byte("not zstd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	corrupt, err := OpenZstdDump(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corrupt.Close()
+
+	if _, err := ImportDump(corrupt, &failingSink{}); err == nil {
+		t.Fatal("Expected a corrupt dump to be reported")
+	}
+}
+
+// TestOpenZstdDumpLongWindow decompress 4KB of random bytes, 9MB of zeros and the same 4KB again, compressed with zstd --long=31 so the second copy refers back past the 8MB window most decoders default to
+func TestOpenZstdDumpLongWindow(t *testing.T) {
+
+	dump, err := OpenZstdDump(filepath.Join("testdata", "long-window.zst"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dump.Close()
+
+	data, err := ioutil.ReadAll(dump)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != 2*4096+9<<20 {
+		t.Fatalf("Expected %d bytes, got %d", 2*4096+9<<20, len(data))
+	}
+
+	if !bytes.Equal(data[:4096], data[len(data)-4096:]) || bytes.Count(data[4096:len(data)-4096], []byte{0}) != 9<<20 {
+		t.Fatal("Decompressed data does not match")
+	}
+}
+
+// Import a monthly Pushshift submissions dump into a file store, the same store live scraping writes to
+func ExampleOpenZstdDump() {
+
+	dump, err := OpenZstdDump("RS_2020-01.zst")
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dump.Close()
+
+	store, err := OpenFileStore("archive.ndjson")
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer store.Close()
+
+	imported, err := ImportDump(dump, NewStoreSink(store))
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Printf("Imported %d items\n", imported)
+}
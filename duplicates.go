@@ -0,0 +1,169 @@
+package rscraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// duplicateDefaultMinLength the shortest normalized body compared by default. Shorter comments, such as "thanks" or "this", are identical too often to mean anything
+const duplicateDefaultMinLength = 20
+
+// DuplicateComments a group of comments with the same normalized body
+type DuplicateComments struct {
+	Hash     string    `json:"hash"`
+	Body     string    `json:"body"`
+	Comments []Comment `json:"comments"`
+
+	// Authors and Threads the distinct authors of the comments and the fullnames of the posts they were made on
+	Authors []string `json:"authors"`
+	Threads []string `json:"threads"`
+}
+
+// DuplicateDetector finds comments with identical bodies, after normalization, across threads and accounts. DuplicateDetector is a Sink, so comments can be written to it from a pipeline or ExportStore
+type DuplicateDetector struct {
+
+	// MinLength comments whose normalized body is shorter than this are ignored
+	MinLength int
+
+	mutex  sync.Mutex
+	groups map[string]*DuplicateComments
+	seen   map[string]bool
+}
+
+// NewDuplicateDetector create a new empty duplicate detector
+func NewDuplicateDetector() *DuplicateDetector {
+
+	return &DuplicateDetector{MinLength: duplicateDefaultMinLength, groups: make(map[string]*DuplicateComments), seen: make(map[string]bool)}
+}
+
+// NormalizeCommentBody reduce a comment body to its words: lower case, without punctuation or markdown, with runs of whitespace collapsed to single spaces
+func NormalizeCommentBody(body string) string {
+
+	words := strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, " ")
+}
+
+// Add record comments. Deleted and removed comments, and comments already added, are ignored
+func (me *DuplicateDetector) Add(comments ...Comment) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, comment := range comments {
+
+		if comment.Body == "[deleted]" || comment.Body == "[removed]" || me.seen[comment.Fullname()] {
+			continue
+		}
+
+		body := NormalizeCommentBody(comment.Body)
+
+		if len(body) < me.MinLength {
+			continue
+		}
+
+		me.seen[comment.Fullname()] = true
+
+		hash := sha256.Sum256([]byte(body))
+
+		key := hex.EncodeToString(hash[:])
+
+		group, ok := me.groups[key]
+
+		if !ok {
+			group = &DuplicateComments{Hash: key, Body: body, Comments: make([]Comment, 0), Authors: make([]string, 0), Threads: make([]string, 0)}
+			me.groups[key] = group
+		}
+
+		group.Comments = append(group.Comments, comment)
+		group.Authors = appendDistinct(group.Authors, comment.Author)
+		group.Threads = appendDistinct(group.Threads, comment.PostID)
+	}
+}
+
+// Write add each Comment, or the comments of each Thread or Snapshot, item. Other items are ignored
+func (me *DuplicateDetector) Write(items ...interface{}) error {
+
+	for _, item := range items {
+
+		switch value := item.(type) {
+		case Comment:
+			me.Add(value)
+		case *Comment:
+			me.Add(*value)
+		case Thread:
+			me.addThread(&value)
+		case *Thread:
+			me.addThread(value)
+		case Snapshot:
+			me.addSnapshot(&value)
+		case *Snapshot:
+			me.addSnapshot(value)
+		}
+	}
+
+	return nil
+}
+
+// Flush does nothing, comments are kept in memory
+func (me *DuplicateDetector) Flush() error {
+
+	return nil
+}
+
+// Duplicates the groups of two or more identical comments, largest first. With acrossThreads set, only groups spanning more than one thread are returned
+func (me *DuplicateDetector) Duplicates(acrossThreads bool) []DuplicateComments {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	duplicates := make([]DuplicateComments, 0)
+
+	for _, group := range me.groups {
+		if len(group.Comments) > 1 && (!acrossThreads || len(group.Threads) > 1) {
+			duplicates = append(duplicates, *group)
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+
+		if len(duplicates[i].Comments) != len(duplicates[j].Comments) {
+			return len(duplicates[i].Comments) > len(duplicates[j].Comments)
+		}
+
+		return duplicates[i].Hash < duplicates[j].Hash
+	})
+
+	return duplicates
+}
+
+func (me *DuplicateDetector) addThread(thread *Thread) {
+
+	if thread.Comments != nil {
+		me.Add(thread.Comments.Flatten()...)
+	}
+}
+
+func (me *DuplicateDetector) addSnapshot(snapshot *Snapshot) {
+
+	if snapshot.Comment != nil {
+		me.Add(*snapshot.Comment)
+	}
+}
+
+func appendDistinct(values []string, value string) []string {
+
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
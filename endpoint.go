@@ -0,0 +1,204 @@
+package rscraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Endpoint describes a reddit API endpoint the package does not wrap. Calls to registered endpoints go through the same request path as the built-in functions, so they are authenticated with the access token, subject to the guardrails' request rate ceiling, recorded to WARC files and retried like batch operations
+type Endpoint struct {
+
+	// Method the HTTP method, GET when empty. POST parameters are sent as a form
+	Method string
+
+	// Path the path of the endpoint, with {name} placeholders filled in from the call's parameters, e.g. /r/{subreddit}/wiki/{page}.json
+	Path string
+
+	// Params the names of the query or form parameters the endpoint accepts. Parameters that are neither placeholders nor listed here are rejected
+	Params []string
+
+	// Decode returns a new value to decode the response into, e.g. func() interface{} { return &WikiPage{} }. When nil the raw response is returned as a json.RawMessage
+	Decode func() interface{}
+
+	// Retry retry failed requests, backing off between attempts. Only enable for endpoints that are safe to repeat
+	Retry bool
+}
+
+var (
+	endpointMutex sync.RWMutex
+	endpoints     = make(map[string]Endpoint)
+
+	endpointPlaceholderRegex = regexp.MustCompile(`\{[^{}/]+\}`)
+)
+
+// RegisterEndpoint register an endpoint under a name to be called with CallEndpoint
+func RegisterEndpoint(name string, endpoint Endpoint) error {
+
+	if name == "" {
+		return errors.New("Endpoint name cannot be empty")
+	}
+
+	if !strings.HasPrefix(endpoint.Path, "/") {
+		return fmt.Errorf("Path of endpoint '%s' must start with /", name)
+	}
+
+	if endpoint.Method == "" {
+		endpoint.Method = "GET"
+	}
+
+	endpoint.Method = strings.ToUpper(endpoint.Method)
+
+	if endpoint.Method != "GET" && endpoint.Method != "POST" {
+		return fmt.Errorf("Endpoint '%s' uses unsupported method %s", name, endpoint.Method)
+	}
+
+	endpointMutex.Lock()
+	defer endpointMutex.Unlock()
+
+	if _, ok := endpoints[name]; ok {
+		return fmt.Errorf("Endpoint '%s' is already registered", name)
+	}
+
+	endpoints[name] = endpoint
+
+	return nil
+}
+
+// CallEndpoint call a registered endpoint, returning the decoded response
+func CallEndpoint(ctx context.Context, name string, params map[string]string) (interface{}, error) {
+
+	endpointMutex.RLock()
+	endpoint, ok := endpoints[name]
+	endpointMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("Endpoint '%s' is not registered", name)
+	}
+
+	redditURL, form, err := endpoint.url(params)
+
+	if err != nil {
+		return nil, fmt.Errorf("Endpoint '%s': %s", name, err.Error())
+	}
+
+	var bytes []byte
+
+	call := func(chunk []string) error {
+		bytes, err = endpoint.call(ctx, redditURL, form)
+		return err
+	}
+
+	if endpoint.Retry {
+		if failures := retryChunks([]string{name}, 1, call); len(failures) > 0 {
+			return nil, failures[name]
+		}
+	} else if err := call(nil); err != nil {
+		return nil, err
+	}
+
+	if endpoint.Decode == nil {
+		return json.RawMessage(bytes), nil
+	}
+
+	result := endpoint.Decode()
+
+	if err := unmarshal(bytes, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// url the URL of a call to the endpoint, with placeholder values escaped as single path segments and the parameters that are not path placeholders in its query for GET requests or in the returned form for POST requests
+func (me *Endpoint) url(params map[string]string) (*url.URL, url.Values, error) {
+
+	missing := ""
+
+	fill := func(escape func(string) string) string {
+
+		return endpointPlaceholderRegex.ReplaceAllStringFunc(me.Path, func(placeholder string) string {
+
+			value, ok := params[placeholder[1:len(placeholder)-1]]
+
+			if !ok {
+				missing = placeholder
+				return placeholder
+			}
+
+			return escape(value)
+		})
+	}
+
+	path := fill(func(value string) string { return value })
+	escaped := fill(url.PathEscape)
+
+	if missing != "" {
+		return nil, nil, fmt.Errorf("Missing parameter for placeholder %s", missing)
+	}
+
+	values := url.Values{}
+
+	for name, value := range params {
+
+		if strings.Contains(me.Path, "{"+name+"}") {
+			continue
+		}
+
+		allowed := false
+
+		for _, param := range me.Params {
+			if param == name {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return nil, nil, fmt.Errorf("Unknown parameter '%s'", name)
+		}
+
+		values.Set(name, value)
+	}
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = path
+	redditURL.RawPath = escaped
+
+	if me.Method == "GET" {
+		redditURL.RawQuery = values.Encode()
+		return redditURL, nil, nil
+	}
+
+	return redditURL, values, nil
+}
+
+func (me *Endpoint) call(ctx context.Context, redditURL *url.URL, form url.Values) ([]byte, error) {
+
+	if me.Method == "GET" {
+		return getContext(ctx, redditURL.String())
+	}
+
+	req, err := http.NewRequest(me.Method, redditURL.String(), strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if token := accessToken(); token != "" {
+		req.Header.Set("Authorization", "bearer "+token)
+	}
+
+	return do(req)
+}
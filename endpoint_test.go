@@ -0,0 +1,40 @@
+package rscraper
+
+import "testing"
+
+func TestEndpointURL(t *testing.T) {
+
+	endpoint := &Endpoint{Method: "GET", Path: "/r/{subreddit}/wiki/{page}.json", Params: []string{"v"}}
+
+	tests := []struct {
+		params map[string]string
+		url    string
+		err    bool
+	}{
+		{params: map[string]string{"subreddit": "golang", "page": "index"}, url: "https://reddit.com/r/golang/wiki/index.json"},
+		{params: map[string]string{"subreddit": "golang", "page": "a b/c"}, url: "https://reddit.com/r/golang/wiki/a%20b%2Fc.json"},
+		{params: map[string]string{"subreddit": "golang", "page": "café"}, url: "https://reddit.com/r/golang/wiki/caf%C3%A9.json"},
+		{params: map[string]string{"subreddit": "golang", "page": "{subreddit}"}, url: "https://reddit.com/r/golang/wiki/%7Bsubreddit%7D.json"},
+		{params: map[string]string{"subreddit": "golang", "page": "index", "v": "2"}, url: "https://reddit.com/r/golang/wiki/index.json?v=2"},
+		{params: map[string]string{"subreddit": "golang"}, err: true},
+		{params: map[string]string{"subreddit": "golang", "page": "index", "unknown": "1"}, err: true},
+	}
+
+	for _, test := range tests {
+
+		redditURL, _, err := endpoint.url(test.params)
+
+		if test.err {
+			if err == nil {
+				t.Errorf("%v: expected an error, got %s", test.params, redditURL)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%v: %s", test.params, err)
+		} else if redditURL.String() != test.url {
+			t.Errorf("%v: expected %s, got %s", test.params, test.url, redditURL)
+		}
+	}
+}
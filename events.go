@@ -0,0 +1,84 @@
+package rscraper
+
+const (
+	// EventTypeAward a post received one or more new awards
+	EventTypeAward = "award"
+
+	// EventTypeFlairChange a post's link flair text changed
+	EventTypeFlairChange = "flair_change"
+
+	// EventTypeLocked a post was locked and can no longer receive comments
+	EventTypeLocked = "locked"
+
+	// EventTypeArchived a post was archived and will no longer change
+	EventTypeArchived = "archived"
+
+	// EventTypeVelocity a watched thread's comment velocity crossed its thresholds
+	EventTypeVelocity = "velocity"
+
+	// EventTypeError an error occurred while re-fetching watched posts
+	EventTypeError = "error"
+)
+
+// PostEvent a change detected between two fetches of the same post
+type PostEvent struct {
+	Type     string
+	PostID   string
+	Post     Post
+	Award    *Award
+	Velocity *ThreadVelocity
+	Old      string
+	New      string
+	Err      error
+}
+
+// ComparePosts compares two fetches of the same post and returns the events describing what changed between them
+func ComparePosts(old, new *Post) []PostEvent {
+
+	events := make([]PostEvent, 0)
+
+	if old == nil || new == nil {
+		return events
+	}
+
+	events = append(events, compareAwards(old, new)...)
+
+	if old.LinkFlairText != new.LinkFlairText {
+		events = append(events, PostEvent{Type: EventTypeFlairChange, PostID: new.ID, Post: *new, Old: old.LinkFlairText, New: new.LinkFlairText})
+	}
+
+	if !old.Locked && new.Locked {
+		events = append(events, PostEvent{Type: EventTypeLocked, PostID: new.ID, Post: *new})
+	}
+
+	if !old.Archived && new.Archived {
+		events = append(events, PostEvent{Type: EventTypeArchived, PostID: new.ID, Post: *new})
+	}
+
+	return events
+}
+
+func compareAwards(old, new *Post) []PostEvent {
+
+	events := make([]PostEvent, 0)
+
+	counts := make(map[string]int)
+
+	for _, award := range old.AllAwardings {
+		counts[award.ID] += award.Count
+	}
+
+	for _, award := range new.AllAwardings {
+
+		if award.Count <= counts[award.ID] {
+			continue
+		}
+
+		received := award
+		received.Count = award.Count - counts[award.ID]
+
+		events = append(events, PostEvent{Type: EventTypeAward, PostID: new.ID, Post: *new, Award: &received})
+	}
+
+	return events
+}
@@ -0,0 +1,171 @@
+package rscraper
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileStore a Store persisting snapshots as newline-delimited JSON in a single file. Snapshots are appended as they are saved and the whole store is held in memory
+type FileStore struct {
+	mutex  sync.Mutex
+	path   string
+	memory *MemoryStore
+	file   *os.File
+}
+
+// OpenFileStore open the store file at path, creating it if it does not exist
+func OpenFileStore(path string) (*FileStore, error) {
+
+	memory := NewMemoryStore()
+
+	if err := readSnapshots(path, memory); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{path: path, memory: memory, file: file}, nil
+}
+
+// SavePost save a snapshot of a post
+func (me *FileStore) SavePost(post Post) error {
+
+	return me.SaveSnapshot(Snapshot{Fullname: post.Fullname(), SavedAt: now(), Post: &post})
+}
+
+// SaveComment save a snapshot of a comment
+func (me *FileStore) SaveComment(comment Comment) error {
+
+	return me.SaveSnapshot(Snapshot{Fullname: comment.Fullname(), SavedAt: now(), Comment: &comment})
+}
+
+// SaveSnapshot append a snapshot to the store file
+func (me *FileStore) SaveSnapshot(snapshot Snapshot) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	bytes, err := json.Marshal(snapshot)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = me.file.Write(append(bytes, '\n')); err != nil {
+		return err
+	}
+
+	return me.memory.SaveSnapshot(snapshot)
+}
+
+// Snapshots call fn with every snapshot in the store
+func (me *FileStore) Snapshots(fn func(snapshot Snapshot) error) error {
+
+	return me.memory.Snapshots(fn)
+}
+
+// Prune delete snapshots according to a retention policy, rewriting the store file
+func (me *FileStore) Prune(policy RetentionPolicy) (int, error) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	deleted, err := me.memory.Prune(policy)
+
+	if err != nil || deleted == 0 {
+		return deleted, err
+	}
+
+	return deleted, me.rewrite()
+}
+
+// Close close the store file
+func (me *FileStore) Close() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.file.Close()
+}
+
+// rewrite replace the store file with the snapshots in memory. The new file is opened before it replaces the old one, so a failed rewrite leaves the store appending to the old file
+func (me *FileStore) rewrite() error {
+
+	temp, err := os.Create(me.path + ".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+
+	err = me.memory.Snapshots(func(snapshot Snapshot) error {
+		return encoder.Encode(snapshot)
+	})
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	file, err := os.OpenFile(temp.Name(), os.O_APPEND|os.O_WRONLY, 0644)
+
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	if err = os.Rename(temp.Name(), me.path); err != nil {
+		file.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+
+	me.file.Close()
+	me.file = file
+
+	return nil
+}
+
+func readSnapshots(path string, store Store) error {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+
+	for decoder.More() {
+
+		var snapshot Snapshot
+
+		if err = decoder.Decode(&snapshot); err != nil {
+			return err
+		}
+
+		if err = store.SaveSnapshot(snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
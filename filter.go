@@ -0,0 +1,505 @@
+package rscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FilterFunc decides whether a scraped item, such as a Post or Comment, should be kept
+type FilterFunc func(item interface{}) bool
+
+type filterToken struct {
+	kind  string
+	value string
+	pos   int
+}
+
+const (
+	filterTokenField    = "field"
+	filterTokenString   = "string"
+	filterTokenNumber   = "number"
+	filterTokenOperator = "operator"
+	filterTokenEnd      = "end"
+)
+
+var filterOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "~", "!", "(", ")"}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// ParseFilter compile a filter expression into a FilterFunc.
+//
+// An expression compares item fields, named by their JSON keys, against literals:
+//
+//	score >= 100 && !(link_flair_text == "Meta" || title ~ "(?i)giveaway")
+//
+// Supported operators are == != < <= > >= for strings, numbers and booleans, ~ and !~ for regular expression matches, and && || ! (or and, or, not) to combine comparisons. Comparisons against fields the item does not have are false
+func ParseFilter(expression string) (FilterFunc, error) {
+
+	tokens, err := tokenizeFilter(expression)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &filterParser{tokens: tokens}
+
+	filter, err := parser.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if token := parser.peek(); token.kind != filterTokenEnd {
+		return nil, fmt.Errorf("Unexpected '%s' in filter expression at position %d", token.value, token.pos)
+	}
+
+	return filter, nil
+}
+
+// LoadFilters read a JSON object mapping filter names to filter expressions and compile each of them
+func LoadFilters(r io.Reader) (map[string]FilterFunc, error) {
+
+	expressions := make(map[string]string)
+
+	if err := json.NewDecoder(r).Decode(&expressions); err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]FilterFunc)
+
+	for name, expression := range expressions {
+
+		filter, err := ParseFilter(expression)
+
+		if err != nil {
+			return nil, fmt.Errorf("Filter '%s': %s", name, err.Error())
+		}
+
+		filters[name] = filter
+	}
+
+	return filters, nil
+}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+
+	tokens := make([]filterToken, 0)
+
+	for i := 0; i < len(expression); {
+
+		c := rune(expression[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+
+			end := i + 1
+
+			for end < len(expression) && expression[end] != '"' {
+				if expression[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			if end >= len(expression) {
+				return nil, fmt.Errorf("Unterminated string in filter expression at position %d", i)
+			}
+
+			value, err := strconv.Unquote(expression[i : end+1])
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid string in filter expression at position %d", i)
+			}
+
+			tokens = append(tokens, filterToken{kind: filterTokenString, value: value, pos: i})
+			i = end + 1
+		case unicode.IsDigit(c) || c == '-' || c == '.':
+
+			end := i + 1
+
+			for end < len(expression) && (unicode.IsDigit(rune(expression[end])) || expression[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, filterToken{kind: filterTokenNumber, value: expression[i:end], pos: i})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+
+			end := i + 1
+
+			for end < len(expression) && (unicode.IsLetter(rune(expression[end])) || unicode.IsDigit(rune(expression[end])) || expression[end] == '_') {
+				end++
+			}
+
+			word := expression[i:end]
+
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, filterToken{kind: filterTokenOperator, value: "&&", pos: i})
+			case "or":
+				tokens = append(tokens, filterToken{kind: filterTokenOperator, value: "||", pos: i})
+			case "not":
+				tokens = append(tokens, filterToken{kind: filterTokenOperator, value: "!", pos: i})
+			default:
+				tokens = append(tokens, filterToken{kind: filterTokenField, value: word, pos: i})
+			}
+
+			i = end
+		default:
+
+			operator := ""
+
+			for _, candidate := range filterOperators {
+				if strings.HasPrefix(expression[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+
+			if operator == "" {
+				return nil, fmt.Errorf("Unexpected character '%c' in filter expression at position %d", c, i)
+			}
+
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, value: operator, pos: i})
+			i += len(operator)
+		}
+	}
+
+	return append(tokens, filterToken{kind: filterTokenEnd, pos: len(expression)}), nil
+}
+
+func (me *filterParser) peek() filterToken {
+
+	return me.tokens[me.pos]
+}
+
+func (me *filterParser) next() filterToken {
+
+	token := me.tokens[me.pos]
+
+	if token.kind != filterTokenEnd {
+		me.pos++
+	}
+
+	return token
+}
+
+func (me *filterParser) accept(operator string) bool {
+
+	if token := me.peek(); token.kind == filterTokenOperator && token.value == operator {
+		me.pos++
+		return true
+	}
+
+	return false
+}
+
+func (me *filterParser) parseOr() (FilterFunc, error) {
+
+	left, err := me.parseAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for me.accept("||") {
+
+		right, err := me.parseAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = orFilter(left, right)
+	}
+
+	return left, nil
+}
+
+func (me *filterParser) parseAnd() (FilterFunc, error) {
+
+	left, err := me.parseNot()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for me.accept("&&") {
+
+		right, err := me.parseNot()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = andFilter(left, right)
+	}
+
+	return left, nil
+}
+
+func (me *filterParser) parseNot() (FilterFunc, error) {
+
+	if me.accept("!") {
+
+		inner, err := me.parseNot()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(item interface{}) bool {
+			return !inner(item)
+		}, nil
+	}
+
+	if me.accept("(") {
+
+		inner, err := me.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !me.accept(")") {
+			return nil, fmt.Errorf("Missing ')' in filter expression at position %d", me.peek().pos)
+		}
+
+		return inner, nil
+	}
+
+	return me.parseComparison()
+}
+
+func (me *filterParser) parseComparison() (FilterFunc, error) {
+
+	field := me.next()
+
+	if field.kind != filterTokenField || field.value == "true" || field.value == "false" {
+		return nil, fmt.Errorf("Expected a field name in filter expression at position %d, found '%s'", field.pos, field.value)
+	}
+
+	operator := me.next()
+
+	if operator.kind != filterTokenOperator {
+		return nil, fmt.Errorf("Expected a comparison after '%s' in filter expression at position %d", field.value, operator.pos)
+	}
+
+	literal := me.next()
+
+	switch operator.value {
+	case "~", "!~":
+
+		if literal.kind != filterTokenString {
+			return nil, fmt.Errorf("Expected a regular expression string after '%s %s' at position %d", field.value, operator.value, literal.pos)
+		}
+
+		pattern, err := regexp.Compile(literal.value)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regular expression in filter expression at position %d: %s", literal.pos, err.Error())
+		}
+
+		return regexFilter(field.value, pattern, operator.value == "!~"), nil
+	case "==", "!=", "<", "<=", ">", ">=":
+
+		switch literal.kind {
+		case filterTokenString:
+			return stringFilter(field.value, operator.value, literal.value), nil
+		case filterTokenNumber:
+
+			number, err := strconv.ParseFloat(literal.value, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid number '%s' in filter expression at position %d", literal.value, literal.pos)
+			}
+
+			return numberFilter(field.value, operator.value, number), nil
+		case filterTokenField:
+
+			if literal.value == "true" || literal.value == "false" {
+				return boolFilter(field.value, operator.value, literal.value == "true")
+			}
+		}
+
+		return nil, fmt.Errorf("Expected a literal after '%s %s' in filter expression at position %d", field.value, operator.value, literal.pos)
+	}
+
+	return nil, fmt.Errorf("Unknown comparison '%s' in filter expression at position %d", operator.value, operator.pos)
+}
+
+func orFilter(left, right FilterFunc) FilterFunc {
+
+	return func(item interface{}) bool {
+		return left(item) || right(item)
+	}
+}
+
+func andFilter(left, right FilterFunc) FilterFunc {
+
+	return func(item interface{}) bool {
+		return left(item) && right(item)
+	}
+}
+
+func regexFilter(field string, pattern *regexp.Regexp, negate bool) FilterFunc {
+
+	return func(item interface{}) bool {
+
+		value, ok := filterField(item, field)
+
+		if !ok || value.Kind() != reflect.String {
+			return false
+		}
+
+		return pattern.MatchString(value.String()) != negate
+	}
+}
+
+func stringFilter(field, operator, literal string) FilterFunc {
+
+	return func(item interface{}) bool {
+
+		value, ok := filterField(item, field)
+
+		if !ok || value.Kind() != reflect.String {
+			return false
+		}
+
+		return compareFilterValues(operator, strings.Compare(value.String(), literal))
+	}
+}
+
+func numberFilter(field, operator string, literal float64) FilterFunc {
+
+	return func(item interface{}) bool {
+
+		value, ok := filterField(item, field)
+
+		if !ok {
+			return false
+		}
+
+		var number float64
+
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = value.Float()
+		default:
+			return false
+		}
+
+		switch {
+		case number < literal:
+			return compareFilterValues(operator, -1)
+		case number > literal:
+			return compareFilterValues(operator, 1)
+		}
+
+		return compareFilterValues(operator, 0)
+	}
+}
+
+func boolFilter(field, operator string, literal bool) (FilterFunc, error) {
+
+	if operator != "==" && operator != "!=" {
+		return nil, fmt.Errorf("Booleans can only be compared with == or != in filter expressions")
+	}
+
+	return func(item interface{}) bool {
+
+		value, ok := filterField(item, field)
+
+		if !ok || value.Kind() != reflect.Bool {
+			return false
+		}
+
+		return (value.Bool() == literal) == (operator == "==")
+	}, nil
+}
+
+func compareFilterValues(operator string, comparison int) bool {
+
+	switch operator {
+	case "==":
+		return comparison == 0
+	case "!=":
+		return comparison != 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	}
+
+	return false
+}
+
+// filterField find the field of a struct item by its JSON key or, failing that, its Go name. Items that are maps, such as the fields of a TransformedItem, are looked up by key
+func filterField(item interface{}, name string) (reflect.Value, bool) {
+
+	value := reflect.ValueOf(item)
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String {
+
+		field := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+
+		for field.IsValid() && field.Kind() == reflect.Interface {
+			field = field.Elem()
+		}
+
+		return field, field.IsValid()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+
+		field := valueType.Field(i)
+
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if tag == name || (tag == "" && strings.EqualFold(field.Name, name)) {
+			return value.Field(i), true
+		}
+	}
+
+	for i := 0; i < valueType.NumField(); i++ {
+		if strings.EqualFold(valueType.Field(i).Name, name) {
+			return value.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
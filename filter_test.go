@@ -0,0 +1,93 @@
+package rscraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+
+	post := Post{Title: `Weekly "Meta" thread`, Score: 150, NumComments: -1, LinkFlairText: "Meta", Author: "someone", Over18: true}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`score >= 100`, true},
+		{`score < 100`, false},
+		{`score == 150 && over_18 == true`, true},
+		{`over_18 != true`, false},
+		{`num_comments == -1`, true},
+		{`num_comments > -2.5`, true},
+
+		// && binds tighter than ||, and ! tighter than both
+		{`score < 0 && score < 0 || score > 0`, true},
+		{`score > 0 || score < 0 && score < 0`, true},
+		{`(score > 0 || score < 0) && score < 0`, false},
+		{`!score < 0 && score > 0`, true},
+		{`!(score < 0 || score > 0)`, false},
+		{`not score < 0 and (score > 200 or link_flair_text == "Meta")`, true},
+		{`NOT score > 0 OR author == "someone"`, true},
+
+		{`title ~ "(?i)^weekly"`, true},
+		{`title !~ "(?i)^weekly"`, false},
+		{`title ~ "\"Meta\""`, true},
+		{`title == "Weekly \"Meta\" thread"`, true},
+		{`link_flair_text == "Meta && Discussion"`, false},
+		{`link_flair_text < "N"`, true},
+
+		// comparisons against missing fields or mismatched types are false
+		{`missing == "x"`, false},
+		{`missing != "x"`, false},
+		{`title > 10`, false},
+		{`score ~ "1"`, false},
+		{`Score == 150`, true},
+	}
+
+	for _, test := range tests {
+
+		filter, err := ParseFilter(test.expression)
+
+		if err != nil {
+			t.Errorf("%s: %s", test.expression, err)
+			continue
+		}
+
+		if result := filter(post); result != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.expression, test.expected, result)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+
+	tests := []struct {
+		expression string
+		message    string
+	}{
+		{`score >= `, "Expected a literal after 'score >=' in filter expression at position 9"},
+		{`score 100`, "Expected a comparison after 'score' in filter expression at position 6"},
+		{`== 100`, "Expected a field name in filter expression at position 0, found '=='"},
+		{`true == score`, "Expected a field name in filter expression at position 0, found 'true'"},
+		{`(score > 1`, "Missing ')' in filter expression at position 10"},
+		{`score > 1)`, "Unexpected ')' in filter expression at position 9"},
+		{`title == "open`, "Unterminated string in filter expression at position 9"},
+		{`title ~ 5`, "Expected a regular expression string after 'title ~' at position 8"},
+		{`title ~ "("`, "Invalid regular expression in filter expression at position 8"},
+		{`score > 1.2.3`, "Invalid number '1.2.3' in filter expression at position 8"},
+		{`score > 1 & score < 2`, "Unexpected character '&' in filter expression at position 10"},
+		{`score ! 1`, "Unknown comparison '!' in filter expression at position 6"},
+		{`over_18 > true`, "Booleans can only be compared with == or != in filter expressions"},
+	}
+
+	for _, test := range tests {
+
+		_, err := ParseFilter(test.expression)
+
+		if err == nil {
+			t.Errorf("%s: expected an error", test.expression)
+		} else if !strings.HasPrefix(err.Error(), test.message) {
+			t.Errorf("%s: expected '%s', got '%s'", test.expression, test.message, err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package rscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// BenchmarkListingPosts the number of posts in the listing fixture, the most reddit returns in one page
+	BenchmarkListingPosts = 100
+
+	// BenchmarkThreadComments the number of comments in the thread fixture, about the size of a busy thread's first response
+	BenchmarkThreadComments = 1500
+
+	// benchmarkSeed the seed fixtures are generated from, so every run benchmarks the same data
+	benchmarkSeed = 1105
+)
+
+var benchmarkWords = strings.Fields(`the a of and to in is it that was for on with as this have be at you not but
+	they from or by one all would there what about when out up just can like time people think know get because
+	more some so really also which very good new even much post thread comment subreddit mod source edit update
+	actually probably never always anyone someone problem question answer thanks great agree point reason year`)
+
+var (
+	benchmarkFixtureOnce sync.Once
+	benchmarkListing     []byte
+	benchmarkThread      []byte
+)
+
+// ListingFixture a subreddit listing page of BenchmarkListingPosts posts, as returned by reddit, generated deterministically
+func ListingFixture() []byte {
+
+	benchmarkFixtureOnce.Do(generateBenchmarkFixtures)
+
+	return benchmarkListing
+}
+
+// ThreadFixture a comments endpoint response for a post with BenchmarkThreadComments nested comments and a few unloaded replies, as returned by reddit, generated deterministically
+func ThreadFixture() []byte {
+
+	benchmarkFixtureOnce.Do(generateBenchmarkFixtures)
+
+	return benchmarkThread
+}
+
+// GenerateListingFixture generate a listing page of posts, realistic in size and shape, from a random seed
+func GenerateListingFixture(posts int, seed int64) []byte {
+
+	random := rand.New(rand.NewSource(seed))
+
+	children := make([]interface{}, posts)
+
+	for i := range children {
+		children[i] = fixturePost(random, i)
+	}
+
+	data, _ := json.Marshal(fixtureListing(children, "t3_"+fixtureID(posts)))
+
+	return data
+}
+
+// GenerateThreadFixture generate a comments endpoint response for a post with the provided number of comments, realistic in size and nesting, from a random seed
+func GenerateThreadFixture(comments int, seed int64) []byte {
+
+	random := rand.New(rand.NewSource(seed))
+
+	post := fixturePost(random, 0)
+
+	remaining := comments
+	next := 1
+
+	var generate func(parent string, depth int) []interface{}
+
+	generate = func(parent string, depth int) []interface{} {
+
+		siblings := 1 + random.Intn(8-depth)
+
+		if depth == 0 {
+			siblings = remaining
+		}
+
+		children := make([]interface{}, 0, siblings)
+
+		for i := 0; i < siblings && remaining > 0; i++ {
+
+			remaining--
+
+			id := fixtureID(next)
+			next++
+
+			comment := fixtureComment(random, id, parent)
+
+			if depth < 7 && random.Intn(3) > 0 {
+				if replies := generate("t1_"+id, depth+1); len(replies) > 0 {
+					comment["data"].(map[string]interface{})["replies"] = fixtureListing(replies, "")
+				}
+			}
+
+			children = append(children, comment)
+		}
+
+		if depth > 0 && random.Intn(10) == 0 {
+			children = append(children, map[string]interface{}{"kind": apiObjectTypeMoreReplies, "data": map[string]interface{}{
+				"count": 3, "parent_id": parent, "children": []string{fixtureID(comments + next), fixtureID(comments + next + 1)},
+			}})
+		}
+
+		return children
+	}
+
+	data, _ := json.Marshal([]interface{}{
+		fixtureListing([]interface{}{post}, ""),
+		fixtureListing(generate("t3_"+fixtureID(0), 0), ""),
+	})
+
+	return data
+}
+
+func generateBenchmarkFixtures() {
+
+	benchmarkListing = GenerateListingFixture(BenchmarkListingPosts, benchmarkSeed)
+	benchmarkThread = GenerateThreadFixture(BenchmarkThreadComments, benchmarkSeed)
+}
+
+func fixtureListing(children []interface{}, after string) map[string]interface{} {
+
+	data := map[string]interface{}{"children": children, "after": nil, "before": nil, "dist": len(children)}
+
+	if after != "" {
+		data["after"] = after
+	}
+
+	return map[string]interface{}{"kind": apiObjectTypeListing, "data": data}
+}
+
+func fixturePost(random *rand.Rand, index int) map[string]interface{} {
+
+	id := fixtureID(index)
+	title := fixtureText(random, 6+random.Intn(12))
+	created := float64(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix() - int64(index*600))
+	score := random.Intn(20000)
+
+	data := map[string]interface{}{
+		"id": id, "name": "t3_" + id, "subreddit": "benchmark", "subreddit_id": "t5_2qh1i", "subreddit_type": "public",
+		"author": fixtureAuthor(random), "author_flair_text": nil, "author_flair_css_class": nil,
+		"link_flair_text": "Discussion", "link_flair_css_class": "discussion", "link_flair_template_id": "3d5a0a52-8a6b-11e8-9bd5-0e1f1ac6f4a0",
+		"title": title, "permalink": "/r/benchmark/comments/" + id + "/" + strings.Replace(title, " ", "_", -1) + "/",
+		"created_utc": created, "created": created, "edited": false, "gilded": 0, "score": score, "ups": score, "downs": 0,
+		"upvote_ratio": 0.5 + random.Float64()/2, "num_comments": random.Intn(2000), "over_18": false, "locked": false,
+		"archived": false, "total_awards_received": 0, "all_awardings": []interface{}{}, "domain": "self.benchmark",
+	}
+
+	if random.Intn(2) == 0 {
+
+		text := fixtureText(random, 40+random.Intn(200))
+
+		data["is_self"] = true
+		data["url"] = "https://www.reddit.com" + data["permalink"].(string)
+		data["selftext"] = text
+		data["selftext_html"] = "&lt;div class=\"md\"&gt;&lt;p&gt;" + text + "&lt;/p&gt;\n&lt;/div&gt;"
+		data["thumbnail"] = "self"
+	} else {
+
+		image := "https://i.redd.it/" + id + "abcdefgh.jpg"
+		resolutions := make([]interface{}, 0)
+
+		for _, width := range []int{108, 216, 320, 640, 960, 1080} {
+			resolutions = append(resolutions, map[string]interface{}{"url": "https://preview.redd.it/" + id + ".jpg?width=" + strconv.Itoa(width) + "&amp;crop=smart&amp;auto=webp&amp;s=0f3c2b1a", "width": width, "height": width * 3 / 4})
+		}
+
+		data["is_self"] = false
+		data["url"] = image
+		data["selftext"] = ""
+		data["selftext_html"] = nil
+		data["thumbnail"] = "https://b.thumbs.redditmedia.com/" + id + ".jpg"
+		data["preview"] = map[string]interface{}{"enabled": true, "images": []interface{}{map[string]interface{}{
+			"id": id, "source": map[string]interface{}{"url": image, "width": 1920, "height": 1440}, "resolutions": resolutions,
+		}}}
+	}
+
+	return map[string]interface{}{"kind": apiObjectTypePost, "data": data}
+}
+
+func fixtureComment(random *rand.Rand, id, parent string) map[string]interface{} {
+
+	body := fixtureText(random, 5+random.Intn(80))
+	created := float64(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix() + int64(random.Intn(86400)))
+	score := random.Intn(500) - 20
+
+	return map[string]interface{}{"kind": apiObjectTypeComment, "data": map[string]interface{}{
+		"id": id, "name": "t1_" + id, "link_id": "t3_" + fixtureID(0), "parent_id": parent, "subreddit": "benchmark",
+		"author": fixtureAuthor(random), "author_flair_text": nil, "author_flair_css_class": nil,
+		"permalink": "/r/benchmark/comments/" + fixtureID(0) + "/_/" + id + "/", "created_utc": created, "created": created,
+		"edited": false, "gilded": 0, "score": score, "ups": score, "downs": 0, "body": body,
+		"body_html": "&lt;div class=\"md\"&gt;&lt;p&gt;" + body + "&lt;/p&gt;\n&lt;/div&gt;", "replies": "",
+	}}
+}
+
+func fixtureText(random *rand.Rand, words int) string {
+
+	text := make([]string, words)
+
+	for i := range text {
+		text[i] = benchmarkWords[random.Intn(len(benchmarkWords))]
+	}
+
+	return strings.Join(text, " ")
+}
+
+func fixtureAuthor(random *rand.Rand) string {
+
+	return fmt.Sprintf("%s_%s%d", benchmarkWords[random.Intn(len(benchmarkWords))], benchmarkWords[random.Intn(len(benchmarkWords))], random.Intn(1000))
+}
+
+func fixtureID(index int) string {
+
+	return strconv.FormatInt(int64(100000000+index), 36)
+}
@@ -0,0 +1,16 @@
+package rscraper
+
+import "testing"
+
+func TestFixturesDecode(t *testing.T) {
+
+	posts, after, err := DecodeListing(ListingFixture())
+
+	if err != nil || len(posts) != BenchmarkListingPosts || after == "" {
+		t.Fatalf("Expected %d posts and a next page, got %d, '%s', %v", BenchmarkListingPosts, len(posts), after, err)
+	}
+
+	if _, comments, err := DecodeThread(ThreadFixture()); err != nil || len(comments) == 0 {
+		t.Fatalf("Expected the thread fixture to decode with comments, got %d, %v", len(comments), err)
+	}
+}
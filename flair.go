@@ -0,0 +1,135 @@
+package rscraper
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FlairTemplate a link flair defined by a subreddit's moderators
+type FlairTemplate struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	CSSClass string `json:"css_class"`
+	ModOnly  bool   `json:"mod_only"`
+}
+
+// FlairCount the number of posts with a link flair. Posts without flair are counted under an empty Flair
+type FlairCount struct {
+	Flair      string `json:"flair"`
+	TemplateID string `json:"template_id"`
+	Posts      int    `json:"posts"`
+}
+
+// GetFlairTemplates retrieve the link flair templates of a subreddit. Reddit only serves this endpoint to authenticated clients, see SetAccessToken
+func GetFlairTemplates(subreddit string) ([]FlairTemplate, error) {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/r/%s/api/link_flair_v2", subreddit)
+
+	bytes, err := get(redditURL.String())
+
+	if err != nil {
+		return nil, subredditError(subreddit, err)
+	}
+
+	templates := make([]FlairTemplate, 0)
+
+	if err = unmarshal(bytes, &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// FlairStats count the posts created in [start, end) per link flair, most used first. Posts using a template are counted under the template's current text, so posts flaired before the template was renamed are counted together with newer ones. Pass nil templates to count by the flair text on each post
+func FlairStats(posts []Post, templates []FlairTemplate, start, end time.Time) []FlairCount {
+
+	names := make(map[string]string, len(templates))
+
+	for _, template := range templates {
+		names[template.ID] = template.Text
+	}
+
+	counts := make(map[string]*FlairCount)
+
+	for _, post := range posts {
+
+		created := createdInstant(post.CreatedUTC)
+
+		if created.Before(start) || !created.Before(end) {
+			continue
+		}
+
+		key, count := "text:"+post.LinkFlairText, FlairCount{Flair: post.LinkFlairText}
+
+		if name, ok := names[post.LinkFlairTemplateID]; ok {
+			key, count = "template:"+post.LinkFlairTemplateID, FlairCount{Flair: name, TemplateID: post.LinkFlairTemplateID}
+		}
+
+		if counts[key] == nil {
+			counts[key] = &count
+		}
+
+		counts[key].Posts++
+	}
+
+	result := make([]FlairCount, 0, len(counts))
+
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+
+		if result[i].Posts != result[j].Posts {
+			return result[i].Posts > result[j].Posts
+		}
+
+		return result[i].Flair < result[j].Flair
+	})
+
+	return result
+}
+
+// GetFlairStats count the posts created in a subreddit in [start, end) per link flair, using the subreddit's flair templates for canonical names. The templates are only readable by some accounts, such as the subreddit's moderators; when they cannot be retrieved posts are counted by their flair text instead. Posts are read from the new listing, so windows reaching further back than reddit's listing cap are undercounted; the returned ListingCapError reports when that happened, alongside the counts
+func GetFlairStats(subreddit string, start, end time.Time) ([]FlairCount, error) {
+
+	templates, err := GetFlairTemplates(subreddit)
+
+	if err != nil {
+		templates = nil
+	}
+
+	posts := make([]Post, 0)
+
+	iterator := NewPostIterator(subreddit, ListingTypeNew, "")
+	defer iterator.Close()
+
+	reachedStart := false
+
+	for iterator.Next() {
+
+		post := iterator.Post()
+
+		if createdInstant(post.CreatedUTC).Before(start) {
+			reachedStart = true
+			break
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := FlairStats(posts, templates, start, end)
+
+	if !reachedStart && iterator.Capped() {
+		return stats, &ListingCapError{Subreddit: subreddit, ListingType: ListingTypeNew, Count: iterator.Count()}
+	}
+
+	return stats, nil
+}
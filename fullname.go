@@ -0,0 +1,81 @@
+package rscraper
+
+const apiPermalinkHost = "https://www.reddit.com"
+
+// AppendFullname append the fullname of an object (e.g. t3_abc123) to dst. An id that is already a fullname has its prefix replaced by kind, so a fullname of another kind is never passed through. Does not allocate when dst has enough capacity
+func AppendFullname(dst []byte, kind, id string) []byte {
+
+	if hasFullnamePrefix(id) {
+		id = id[3:]
+	}
+
+	dst = append(dst, kind...)
+	dst = append(dst, '_')
+
+	return append(dst, id...)
+}
+
+// AppendPermalink append the absolute URL of a permalink (e.g. /r/golang/comments/abc123/) to dst. Does not allocate when dst has enough capacity
+func AppendPermalink(dst []byte, permalink string) []byte {
+
+	dst = append(dst, apiPermalinkHost...)
+
+	return append(dst, permalink...)
+}
+
+// AppendPostPermalink append the absolute URL of a post in a subreddit to dst. Does not allocate when dst has enough capacity
+func AppendPostPermalink(dst []byte, subreddit, postID string) []byte {
+
+	if hasFullnamePrefix(postID) {
+		postID = postID[3:]
+	}
+
+	dst = append(dst, apiPermalinkHost...)
+	dst = append(dst, "/r/"...)
+	dst = append(dst, subreddit...)
+	dst = append(dst, "/comments/"...)
+	dst = append(dst, postID...)
+
+	return append(dst, '/')
+}
+
+// Fullname the fullname of the post (e.g. t3_abc123). Allocates the returned string; use AppendFullname with a reused buffer in hot paths
+func (me *Post) Fullname() string {
+
+	return string(AppendFullname(make([]byte, 0, len(apiObjectTypePost)+1+len(me.ID)), apiObjectTypePost, me.ID))
+}
+
+// AppendFullname append the fullname of the post to dst. Does not allocate when dst has enough capacity
+func (me *Post) AppendFullname(dst []byte) []byte {
+
+	return AppendFullname(dst, apiObjectTypePost, me.ID)
+}
+
+// AppendPermalink append the absolute URL of the post to dst. Does not allocate when dst has enough capacity
+func (me *Post) AppendPermalink(dst []byte) []byte {
+
+	return AppendPermalink(dst, me.PermaLink)
+}
+
+// Fullname the fullname of the comment (e.g. t1_abc123). Allocates the returned string; use AppendFullname with a reused buffer in hot paths
+func (me *Comment) Fullname() string {
+
+	return string(AppendFullname(make([]byte, 0, len(apiObjectTypeComment)+1+len(me.ID)), apiObjectTypeComment, me.ID))
+}
+
+// AppendFullname append the fullname of the comment to dst. Does not allocate when dst has enough capacity
+func (me *Comment) AppendFullname(dst []byte) []byte {
+
+	return AppendFullname(dst, apiObjectTypeComment, me.ID)
+}
+
+// AppendPermalink append the absolute URL of the comment to dst. Does not allocate when dst has enough capacity
+func (me *Comment) AppendPermalink(dst []byte) []byte {
+
+	return AppendPermalink(dst, me.PermaLink)
+}
+
+func hasFullnamePrefix(id string) bool {
+
+	return len(id) > 3 && id[0] == 't' && id[2] == '_' && id[1] >= '1' && id[1] <= '6'
+}
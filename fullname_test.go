@@ -0,0 +1,65 @@
+package rscraper
+
+import "testing"
+
+func TestAppendFullname(t *testing.T) {
+
+	tests := []struct {
+		kind, id, want string
+	}{
+		{apiObjectTypePost, "abc123", "t3_abc123"},
+		{apiObjectTypePost, "t3_abc123", "t3_abc123"},
+		{apiObjectTypePost, "t1_abc123", "t3_abc123"},
+		{apiObjectTypeComment, "t3_abc123", "t1_abc123"},
+		{apiObjectTypeComment, "t9_abc123", "t1_t9_abc123"},
+	}
+
+	for _, test := range tests {
+		if got := string(AppendFullname(nil, test.kind, test.id)); got != test.want {
+			t.Errorf("AppendFullname(%q, %q) = %q, want %q", test.kind, test.id, got, test.want)
+		}
+	}
+}
+
+func BenchmarkAppendFullname(b *testing.B) {
+
+	buf := make([]byte, 0, 32)
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = AppendFullname(buf[:0], apiObjectTypePost, "abc123") }); allocs != 0 {
+		b.Fatalf("AppendFullname allocated %v times per call, want 0", allocs)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf = AppendFullname(buf[:0], apiObjectTypePost, "abc123")
+	}
+}
+
+func BenchmarkAppendPostPermalink(b *testing.B) {
+
+	buf := make([]byte, 0, 128)
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = AppendPostPermalink(buf[:0], "golang", "t3_abc123") }); allocs != 0 {
+		b.Fatalf("AppendPostPermalink allocated %v times per call, want 0", allocs)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf = AppendPostPermalink(buf[:0], "golang", "t3_abc123")
+	}
+}
+
+func BenchmarkPostFullname(b *testing.B) {
+
+	post := Post{ID: "abc123"}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = post.Fullname()
+	}
+}
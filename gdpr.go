@@ -0,0 +1,185 @@
+package rscraper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gdprTimeLayout the layout of dates in reddit's personal data export
+const gdprTimeLayout = "2006-01-02 15:04:05 MST"
+
+// ReadGDPRPosts read the posts.csv file of a reddit personal data export. The export does not name the account, so every post is attributed to author
+func ReadGDPRPosts(r io.Reader, author string) ([]Post, error) {
+
+	posts := make([]Post, 0)
+
+	err := readGDPRFile(r, []string{"id", "permalink", "date"}, func(row map[string]string) error {
+
+		created, err := parseGDPRTime(row["date"])
+
+		if err != nil {
+			return err
+		}
+
+		post := Post{
+			ID:         row["id"],
+			Subreddit:  row["subreddit"],
+			Author:     author,
+			Title:      row["title"],
+			URL:        row["url"],
+			PermaLink:  gdprPath(row["permalink"]),
+			CreatedUTC: float64(created.Unix()),
+			Text:       row["body"],
+			CreatedOn:  redditTime(float64(created.Unix())),
+		}
+
+		post.IsSelf = post.URL == "" || post.URL == apiPermalinkHost+post.PermaLink
+
+		posts = append(posts, post)
+
+		return nil
+	})
+
+	return posts, err
+}
+
+// ReadGDPRComments read the comments.csv file of a reddit personal data export. The export does not name the account, so every comment is attributed to author
+func ReadGDPRComments(r io.Reader, author string) ([]Comment, error) {
+
+	comments := make([]Comment, 0)
+
+	err := readGDPRFile(r, []string{"id", "permalink", "date", "link"}, func(row map[string]string) error {
+
+		created, err := parseGDPRTime(row["date"])
+
+		if err != nil {
+			return err
+		}
+
+		postID := gdprPostID(row["link"])
+
+		if postID == "" {
+			return fmt.Errorf("Comment '%s' links to '%s', which is not a post", row["id"], row["link"])
+		}
+
+		comment := Comment{
+			ID:         row["id"],
+			PostID:     postID,
+			ParentID:   postID,
+			Subreddit:  row["subreddit"],
+			Author:     author,
+			PermaLink:  gdprPath(row["permalink"]),
+			CreatedUTC: float64(created.Unix()),
+			Body:       row["body"],
+			CreatedOn:  redditTime(float64(created.Unix())),
+		}
+
+		if parent := row["parent"]; hasFullnamePrefix(parent) {
+			comment.ParentID = parent
+		} else if parent != "" {
+			comment.ParentID = string(AppendFullname(nil, apiObjectTypeComment, parent))
+		}
+
+		comments = append(comments, comment)
+
+		return nil
+	})
+
+	return comments, err
+}
+
+// readGDPRFile call fn with each row of an export CSV file, keyed by column name
+func readGDPRFile(r io.Reader, required []string, fn func(row map[string]string) error) error {
+
+	reader := csv.NewReader(r)
+
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+
+	if err != nil {
+		return err
+	}
+
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	columns := make(map[string]bool, len(header))
+
+	for _, column := range header {
+		columns[column] = true
+	}
+
+	for _, column := range required {
+		if !columns[column] {
+			return fmt.Errorf("Export file is missing the '%s' column", column)
+		}
+	}
+
+	for line := 2; ; line++ {
+
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		row := make(map[string]string, len(header))
+
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return fmt.Errorf("Line %d: %s", line, err.Error())
+		}
+	}
+}
+
+func parseGDPRTime(value string) (time.Time, error) {
+
+	created, err := time.Parse(gdprTimeLayout, value)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid date '%s'", value)
+	}
+
+	return created, nil
+}
+
+// gdprPath the path of a permalink, which the export records as an absolute URL
+func gdprPath(permalink string) string {
+
+	parsed, err := url.Parse(permalink)
+
+	if err != nil || parsed.Host == "" {
+		return permalink
+	}
+
+	return parsed.Path
+}
+
+// gdprPostID the fullname of the post an absolute or relative comments URL points to
+func gdprPostID(link string) string {
+
+	parts := strings.Split(gdprPath(link), "/")
+
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == "comments" && parts[i+1] != "" {
+			return string(AppendFullname(nil, apiObjectTypePost, parts[i+1]))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,80 @@
+package rscraper
+
+import (
+	"context"
+	"time"
+)
+
+// GrowthPoint one sample of a subreddit's size, in a flat schema suited to time-series databases
+type GrowthPoint struct {
+	Time        time.Time `json:"time"`
+	Subreddit   string    `json:"subreddit"`
+	Subscribers int       `json:"subscribers"`
+	ActiveUsers int       `json:"active_users"`
+}
+
+// GrowthCollector records the subscriber and active user counts of a set of subreddits to a sink on a schedule
+type GrowthCollector struct {
+	Tracker SubredditTracker
+	Sink    Sink
+}
+
+// NewGrowthCollector create a new collector sampling the provided subreddits at an interval and writing GrowthPoints to sink
+func NewGrowthCollector(sink Sink, interval time.Duration, subreddits ...string) *GrowthCollector {
+
+	return &GrowthCollector{Tracker: *NewSubredditTracker(interval, subreddits...), Sink: sink}
+}
+
+// Run sample the subreddits until the context is cancelled. Each sample is written and flushed to the sink as it is taken. Errors do not stop the collector; they are delivered on the returned channel if the consumer is receiving from it and dropped otherwise
+func (me *GrowthCollector) Run(ctx context.Context) <-chan error {
+
+	errs := make(chan error, 1)
+
+	tracker := me.Tracker
+
+	tracker.OnSnapshot = func(snapshot SubredditSnapshot) {
+
+		point := GrowthPoint{
+			Time:        snapshot.TakenAt.UTC(),
+			Subreddit:   snapshot.Subreddit.Name,
+			Subscribers: snapshot.Subreddit.Subscribers,
+			ActiveUsers: snapshot.Subreddit.ActiveUsers,
+		}
+
+		err := me.Sink.Write(point)
+
+		if err == nil {
+			err = me.Sink.Flush()
+		}
+
+		if err != nil {
+			sendError(errs, err)
+		}
+	}
+
+	changes, trackerErrs := tracker.Watch(ctx)
+
+	go func() {
+
+		defer close(errs)
+
+		for {
+			select {
+			case _, ok := <-changes:
+				if !ok {
+					return
+				}
+			case err, ok := <-trackerErrs:
+
+				if !ok {
+					trackerErrs = nil
+					continue
+				}
+
+				sendError(errs, err)
+			}
+		}
+	}()
+
+	return errs
+}
@@ -0,0 +1,142 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// GuardrailNSFW the content is marked NSFW and NSFW content is excluded
+	GuardrailNSFW = "nsfw"
+
+	// GuardrailPrivate the content belongs to a private subreddit and private content is refused
+	GuardrailPrivate = "private"
+)
+
+// Guardrails compliance limits enforced on every request made by the package
+type Guardrails struct {
+
+	// MaxRequestsPerMinute the ceiling on request rate. Requests beyond it wait for a free slot. Zero means no ceiling
+	MaxRequestsPerMinute int
+
+	// ExcludeNSFW drop NSFW posts and comments from listings, user activity and batch lookups, and refuse to retrieve NSFW subreddits and threads
+	ExcludeNSFW bool
+
+	// RefusePrivate drop posts and comments from private subreddits and refuse to retrieve private subreddits and their threads, even when an access token grants access to them
+	RefusePrivate bool
+}
+
+// GuardrailError returned when a request is refused by the configured guardrails
+type GuardrailError struct {
+	Rule   string
+	Target string
+}
+
+func (me *GuardrailError) Error() string {
+
+	return fmt.Sprintf("Guardrail '%s' refused access to %s", me.Rule, me.Target)
+}
+
+var (
+	guardrailsMutex sync.Mutex
+	guardrails      Guardrails
+	lastRequest     time.Time
+)
+
+// SetGuardrails replace the guardrails enforced by the package
+func SetGuardrails(g Guardrails) {
+
+	guardrailsMutex.Lock()
+	defer guardrailsMutex.Unlock()
+
+	guardrails = g
+}
+
+func currentGuardrails() Guardrails {
+
+	guardrailsMutex.Lock()
+	defer guardrailsMutex.Unlock()
+
+	return guardrails
+}
+
+// waitForRequestSlot block until a request can be made without exceeding the request rate ceiling, or the context is cancelled
+func waitForRequestSlot(ctx context.Context) error {
+
+	guardrailsMutex.Lock()
+
+	if guardrails.MaxRequestsPerMinute <= 0 {
+		guardrailsMutex.Unlock()
+		return ctx.Err()
+	}
+
+	spacing := time.Minute / time.Duration(guardrails.MaxRequestsPerMinute)
+
+	slot := lastRequest.Add(spacing)
+	current := now()
+
+	if slot.Before(current) {
+		slot = current
+	}
+
+	lastRequest = slot
+
+	guardrailsMutex.Unlock()
+
+	if wait := slot.Sub(current); wait > 0 {
+		select {
+		case <-after(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func guardrailsAllowPost(post *Post) bool {
+
+	return guardrailsCheckPost(post) == nil
+}
+
+func guardrailsCheckPost(post *Post) error {
+
+	return guardrailsCheckContent(post.Over18, post.SubredditType, "post '"+post.ID+"'")
+}
+
+func guardrailsAllowComment(comment *Comment) bool {
+
+	return guardrailsCheckContent(comment.Over18, comment.SubredditType, "comment '"+comment.ID+"'") == nil
+}
+
+func guardrailsCheckContent(over18 bool, subredditType, target string) error {
+
+	g := currentGuardrails()
+
+	if g.ExcludeNSFW && over18 {
+		return &GuardrailError{Rule: GuardrailNSFW, Target: target}
+	}
+
+	if g.RefusePrivate && subredditType == SubredditStatusPrivate {
+		return &GuardrailError{Rule: GuardrailPrivate, Target: target}
+	}
+
+	return nil
+}
+
+func guardrailsCheckSubreddit(subreddit *Subreddit) error {
+
+	g := currentGuardrails()
+
+	if g.ExcludeNSFW && subreddit.Over18 {
+		return &GuardrailError{Rule: GuardrailNSFW, Target: "subreddit '" + subreddit.Name + "'"}
+	}
+
+	if g.RefusePrivate && subreddit.SubredditType == SubredditStatusPrivate {
+		return &GuardrailError{Rule: GuardrailPrivate, Target: "subreddit '" + subreddit.Name + "'"}
+	}
+
+	return nil
+}
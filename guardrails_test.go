@@ -0,0 +1,53 @@
+package rscraper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestGuardrailsFilterEveryPath(t *testing.T) {
+
+	SetGuardrails(Guardrails{ExcludeNSFW: true})
+	defer SetGuardrails(Guardrails{})
+
+	listing := []byte(`{"kind":"Listing","data":{"children":[
+		{"kind":"t3","data":{"id":"aaaaa","over_18":true}},
+		{"kind":"t3","data":{"id":"bbbbb"}}]}}`)
+
+	posts, _, listed, err := parsePostListingCount(listing)
+
+	if err != nil || len(posts) != 1 || listed != 2 {
+		t.Fatalf("Listing kept %d of %d posts: %v", len(posts), listed, err)
+	}
+
+	var objects []apiObject
+
+	thread := `[{"kind":"Listing","data":{"children":[{"kind":"t3","data":{"id":"aaaaa","over_18":true}}]}},{"kind":"Listing","data":{"children":[]}}]`
+
+	if err := json.Unmarshal([]byte(thread), &objects); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := extractThread(objects); err == nil {
+		t.Fatal("Expected a guardrail error for an NSFW thread")
+	} else if _, ok := err.(*GuardrailError); !ok {
+		t.Fatalf("Expected a guardrail error, got %v", err)
+	}
+}
+
+func TestWaitForRequestSlotCancelled(t *testing.T) {
+
+	SetGuardrails(Guardrails{MaxRequestsPerMinute: 1})
+	defer SetGuardrails(Guardrails{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	waitForRequestSlot(ctx)
+
+	cancel()
+
+	if err := waitForRequestSlot(ctx); err == nil {
+		t.Fatal("Expected a cancelled wait to return the context's error")
+	}
+}
@@ -0,0 +1,166 @@
+package rscraper
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	apiObjectTypeMessage = "t4"
+
+	// MessageTypeMention the message is a comment mentioning the user
+	MessageTypeMention = "username_mention"
+
+	// MessageTypeCommentReply the message is a reply to one of the user's comments
+	MessageTypeCommentReply = "comment_reply"
+
+	// MessageTypePostReply the message is a top level comment on one of the user's posts
+	MessageTypePostReply = "post_reply"
+)
+
+// Message an item in the authenticated user's inbox
+type Message struct {
+	ID         string  `json:"id"`
+	Fullname   string  `json:"name"`
+	Type       string  `json:"type"`
+	Author     string  `json:"author"`
+	Subject    string  `json:"subject"`
+	Body       string  `json:"body"`
+	BodyHTML   string  `json:"body_html"`
+	Subreddit  string  `json:"subreddit"`
+	LinkTitle  string  `json:"link_title"`
+	ParentID   string  `json:"parent_id"`
+	Context    string  `json:"context"`
+	WasComment bool    `json:"was_comment"`
+	CreatedUTC float64 `json:"created_utc"`
+	CreatedOn  time.Time
+}
+
+// GetUnreadMessages retrieves the unread items in the authenticated user's inbox, newest first. Requires an access token, see SetAccessToken
+func GetUnreadMessages() ([]Message, error) {
+
+	messages := make([]Message, 0)
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = "/message/unread.json"
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := extractListing(object)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range list.Children {
+
+		message, err := extractMessage(&child)
+
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, *message)
+	}
+
+	return messages, nil
+}
+
+// MarkRead mark inbox items as read. Requires an access token, see SetAccessToken
+func MarkRead(fullnames ...string) error {
+
+	if len(fullnames) == 0 {
+		return nil
+	}
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = "/api/read_message"
+
+	form := url.Values{}
+
+	form.Set("id", strings.Join(fullnames, ","))
+
+	_, err := post(redditURL.String(), form)
+
+	return err
+}
+
+// StreamInbox poll the authenticated user's unread inbox until the context is cancelled, emitting each new username mention and comment or post reply once, oldest first. If markRead is true, emitted items are marked as read. Errors do not stop the stream; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise. Requires an access token, see SetAccessToken
+func StreamInbox(ctx context.Context, interval time.Duration, markRead bool) (<-chan Message, <-chan error) {
+
+	messages := make(chan Message)
+	errs := make(chan error, 1)
+
+	go func() {
+
+		defer close(messages)
+		defer close(errs)
+
+		seen := newSeenSet(streamSeenLimit)
+
+		for {
+			page, err := GetUnreadMessages()
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			read := make([]string, 0)
+
+			for i := len(page) - 1; i >= 0; i-- {
+
+				if !page[i].WasComment || !seen.add(page[i].Fullname) {
+					continue
+				}
+
+				select {
+				case messages <- page[i]:
+					read = append(read, page[i].Fullname)
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if markRead {
+				if err := MarkRead(read...); err != nil {
+					sendError(errs, err)
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+func extractMessage(object *apiObject) (*Message, error) {
+
+	if object == nil || (object.Type != apiObjectTypeMessage && object.Type != apiObjectTypeComment) {
+		return nil, errors.New("Provided API Object is not a Message")
+	}
+
+	var result Message
+
+	err := unmarshal(object.Data, &result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.CreatedOn = redditTime(result.CreatedUTC)
+	return &result, err
+}
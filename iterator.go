@@ -0,0 +1,205 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+)
+
+// apiListingCap the approximate maximum number of items reddit will page through in a single listing
+const apiListingCap = 1000
+
+// apiListingCapThreshold listings that end after at least this many items are assumed to have been cut off by the listing cap
+const apiListingCapThreshold = 900
+
+// ListingCapError returned when a listing ended because reddit stopped paging, not because there were no more items
+type ListingCapError struct {
+	Subreddit   string
+	ListingType string
+	Count       int
+}
+
+func (me *ListingCapError) Error() string {
+
+	return fmt.Sprintf("Listing '%s' of subreddit '%s' ended after %d items, likely truncated by reddit's %d item listing cap", me.ListingType, me.Subreddit, me.Count, apiListingCap)
+}
+
+// PostIterator pages through every post in a subreddit listing
+type PostIterator struct {
+	Subreddit   string
+	ListingType string
+	TopType     string
+
+	// Cache when set, pages are read from and saved to the cache, so the listing can be iterated again without fetching it from reddit
+	Cache PageCache
+
+	// Prefetch fetch the next page in the background while the current page is being consumed. Background fetches are still subject to the guardrails' request rate ceiling. Pages are only fetched while the memory budget is not spent, see SetMemoryBudget
+	Prefetch bool
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending chan postPage
+	pages   int
+	after   string
+	page    []Post
+	current Post
+	count   int
+	listed  int
+	started bool
+	done    bool
+	capped  bool
+	err     error
+}
+
+type postPage struct {
+	posts  []Post
+	after  string
+	listed int
+	err    error
+}
+
+// NewPostIterator create a new iterator over a subreddit listing
+func NewPostIterator(subreddit, listingType, topType string) *PostIterator {
+
+	return NewPostIteratorContext(context.Background(), subreddit, listingType, topType)
+}
+
+// NewPostIteratorContext create a new iterator over a subreddit listing that stops once the context is cancelled, including any page it is waiting to fetch
+func NewPostIteratorContext(ctx context.Context, subreddit, listingType, topType string) *PostIterator {
+
+	iterator := &PostIterator{Subreddit: subreddit, ListingType: listingType, TopType: topType}
+
+	iterator.ctx, iterator.cancel = context.WithCancel(ctx)
+
+	return iterator
+}
+
+// Close stop the iteration, abandoning any page being fetched in the background. Iterators that were not run to the end should be closed so a background fetch waiting on the memory budget does not wait forever
+func (me *PostIterator) Close() {
+
+	if me.cancel != nil {
+		me.cancel()
+	}
+
+	me.done = true
+	me.page = nil
+}
+
+// Next advance to the next post, loading the next page when needed. Returns false when the listing is exhausted or an error occurred
+func (me *PostIterator) Next() bool {
+
+	for len(me.page) == 0 {
+
+		if me.done || me.err != nil {
+			return false
+		}
+
+		if me.started && me.after == "" {
+			me.finish()
+			return false
+		}
+
+		if me.ctx == nil {
+			me.ctx, me.cancel = context.WithCancel(context.Background())
+		}
+
+		me.started = true
+
+		me.page, me.after, me.err = me.nextPage()
+	}
+
+	me.current = me.page[0]
+	me.page = me.page[1:]
+	me.count++
+
+	return true
+}
+
+// Post the post the iterator is currently on
+func (me *PostIterator) Post() Post {
+
+	return me.current
+}
+
+// Err the error that stopped the iteration, if any
+func (me *PostIterator) Err() error {
+
+	return me.err
+}
+
+// Count the number of posts returned so far
+func (me *PostIterator) Count() int {
+
+	return me.count
+}
+
+// Capped whether the listing was exhausted because it hit reddit's listing cap, meaning older posts could not be reached. Posts dropped by the guardrails still count towards the cap
+func (me *PostIterator) Capped() bool {
+
+	return me.capped
+}
+
+// nextPage retrieve the page after the current cursor, from a background prefetch if one is pending, and start prefetching the page after it
+func (me *PostIterator) nextPage() ([]Post, string, error) {
+
+	var page postPage
+
+	if me.pending != nil {
+		select {
+		case page = <-me.pending:
+		case <-me.ctx.Done():
+			page.err = me.ctx.Err()
+		}
+		me.pending = nil
+	} else {
+		page = me.fetch(me.after, me.pages)
+	}
+
+	me.pages++
+	me.listed += page.listed
+
+	if me.Prefetch && page.err == nil && page.after != "" {
+
+		me.pending = make(chan postPage, 1)
+
+		go func(pending chan<- postPage, after string, index int) {
+			pending <- me.fetch(after, index)
+		}(me.pending, page.after, me.pages)
+	}
+
+	return page.posts, page.after, page.err
+}
+
+func (me *PostIterator) fetch(after string, index int) postPage {
+
+	if err := waitForBudget(me.ctx); err != nil {
+		return postPage{err: err}
+	}
+
+	redditURL := getPostsURL(me.Subreddit, me.ListingType, after, me.TopType)
+
+	data, fetchedAt, cached, err := getCached(me.Cache, redditURL)
+
+	if err != nil {
+		return postPage{err: subredditError(me.Subreddit, err)}
+	}
+
+	var page postPage
+
+	page.posts, page.after, page.listed, page.err = parsePostListingCount(data)
+
+	provenance := newProvenance(redditURL.String(), index, cached)
+
+	if !fetchedAt.IsZero() {
+		provenance.FetchedAt = fetchedAt.UTC()
+	}
+
+	setPostProvenance(page.posts, provenance)
+
+	return page
+}
+
+func (me *PostIterator) finish() {
+
+	me.done = true
+	me.capped = me.listed >= apiListingCapThreshold
+}
@@ -0,0 +1,328 @@
+package rscraper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// leaseGuardStale how old a lease file's guard must be before it is assumed to belong to a crashed process and removed
+	leaseGuardStale = 10 * time.Second
+
+	// leaseGuardRetry how long to wait before trying again to take a lease file's guard held by another process
+	leaseGuardRetry = 20 * time.Millisecond
+
+	// leaseGuardAttempts how many times to try to take a lease file's guard before giving up
+	leaseGuardAttempts = 50
+)
+
+// LeaseStore grants time limited exclusive leases on named resources, such as the feed of a subreddit, so several crawler instances can share work without scraping the same feed twice
+type LeaseStore interface {
+
+	// Acquire take, or renew, the lease on name for holder. Returns false when another holder's lease has not yet expired
+	Acquire(name, holder string, ttl time.Duration) (bool, error)
+
+	// Release give up holder's lease on name, if it holds it
+	Release(name, holder string) error
+}
+
+type leaseGuard struct {
+	Token   string    `json:"token"`
+	Created time.Time `json:"created"`
+}
+
+type leaseRecord struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// FileLeaseStore a LeaseStore keeping one file per lease in a directory, which may be shared between machines over a network file system that supports exclusive file creation
+type FileLeaseStore struct {
+	Dir string
+}
+
+// NewFileLeaseStore create a new lease store in dir, creating the directory if needed
+func NewFileLeaseStore(dir string) (*FileLeaseStore, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileLeaseStore{Dir: dir}, nil
+}
+
+// DefaultLeaseHolder a holder name identifying this process, its host name and process ID
+func DefaultLeaseHolder() string {
+
+	host, err := os.Hostname()
+
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Acquire take or renew the lease on name for holder
+func (me *FileLeaseStore) Acquire(name, holder string, ttl time.Duration) (bool, error) {
+
+	acquired := false
+
+	err := me.guarded(name, func(path string) error {
+
+		record, err := readLease(path)
+
+		if err != nil {
+			return err
+		}
+
+		current := now()
+
+		if record.Holder != "" && record.Holder != holder && current.Before(record.Expires) {
+			return nil
+		}
+
+		acquired = true
+
+		return writeLease(path, leaseRecord{Holder: holder, Expires: current.Add(ttl)})
+	})
+
+	return acquired, err
+}
+
+// Release give up holder's lease on name
+func (me *FileLeaseStore) Release(name, holder string) error {
+
+	return me.guarded(name, func(path string) error {
+
+		record, err := readLease(path)
+
+		if err != nil || record.Holder != holder {
+			return err
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// guarded run fn on the path of a lease file while holding its guard, a file next to it created exclusively with a unique token. A guard older than leaseGuardStale is moved aside and removed, unless it turns out to have been replaced by a live guard in the meantime
+func (me *FileLeaseStore) guarded(name string, fn func(path string) error) error {
+
+	path := filepath.Join(me.Dir, url.PathEscape(name)+".lease")
+	guard := path + ".guard"
+
+	token := newLeaseToken()
+
+	for attempt := 0; ; attempt++ {
+
+		err := createLeaseGuard(guard, leaseGuard{Token: token, Created: now()})
+
+		if err == nil {
+
+			defer removeLeaseGuard(guard, token)
+
+			return fn(path)
+		}
+
+		if !os.IsExist(err) {
+			return err
+		}
+
+		if held, err := readLeaseGuard(guard); err == nil && now().Sub(held.Created) > leaseGuardStale {
+			removeLeaseGuard(guard, held.Token)
+			continue
+		}
+
+		if attempt >= leaseGuardAttempts {
+			return fmt.Errorf("Timed out waiting for the guard of lease '%s'", name)
+		}
+
+		<-after(leaseGuardRetry)
+	}
+}
+
+func newLeaseToken() string {
+
+	token := make([]byte, 16)
+
+	rand.Read(token)
+
+	return hex.EncodeToString(token)
+}
+
+// createLeaseGuard write the guard to a file of its own and link it into place, so the guard never exists without its token
+func createLeaseGuard(path string, guard leaseGuard) error {
+
+	data, err := json.Marshal(guard)
+
+	if err != nil {
+		return err
+	}
+
+	temp := path + "." + guard.Token
+
+	if err := ioutil.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+
+	defer os.Remove(temp)
+
+	if err := os.Link(temp, path); err != nil {
+
+		if linkErr, ok := err.(*os.LinkError); ok && os.IsExist(linkErr.Err) {
+			return os.ErrExist
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func readLeaseGuard(path string) (leaseGuard, error) {
+
+	var guard leaseGuard
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return guard, err
+	}
+
+	err = json.Unmarshal(data, &guard)
+
+	return guard, err
+}
+
+// removeLeaseGuard remove the guard at path if it still carries token. The guard is renamed aside before its token is checked, so a guard created by another process in the meantime is put back rather than removed
+func removeLeaseGuard(path, token string) {
+
+	aside := path + "." + newLeaseToken() + ".removed"
+
+	if err := os.Rename(path, aside); err != nil {
+		return
+	}
+
+	defer os.Remove(aside)
+
+	if guard, err := readLeaseGuard(aside); err == nil && guard.Token != token {
+		os.Link(aside, path)
+	}
+}
+
+func readLease(path string) (leaseRecord, error) {
+
+	var record leaseRecord
+
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return record, nil
+	}
+
+	if err != nil {
+		return record, err
+	}
+
+	err = json.Unmarshal(data, &record)
+
+	return record, err
+}
+
+func writeLease(path string, record leaseRecord) error {
+
+	data, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// WithLease wait until holder acquires the lease on name, then run fn with a context that is cancelled if the lease is lost. The lease is renewed every third of its ttl while fn runs and released when fn returns. A renewal that fails with an error is retried at the next renewal as long as the lease has not expired yet, so a single failed renewal does not cancel fn. The lease is only released once any renewal in progress has finished. Standby instances calling WithLease for the same name take over once the active instance stops renewing. Returns when fn returns or the context is cancelled
+func WithLease(ctx context.Context, store LeaseStore, name, holder string, ttl time.Duration, fn func(ctx context.Context) error) error {
+
+	if ttl <= 0 {
+		return errors.New("Lease TTL must be positive")
+	}
+
+	for {
+		acquired, err := store.Acquire(name, holder, ttl)
+
+		if err != nil {
+			return err
+		}
+
+		if acquired {
+			break
+		}
+
+		select {
+		case <-after(ttl / 3):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	expires := now().Add(ttl)
+
+	renewing := make(chan struct{})
+
+	go func() {
+
+		defer close(renewing)
+
+		for {
+			select {
+			case <-after(ttl / 3):
+			case <-leaseCtx.Done():
+				return
+			}
+
+			acquired, err := store.Acquire(name, holder, ttl)
+
+			if err == nil && acquired {
+				expires = now().Add(ttl)
+				continue
+			}
+
+			if err == nil || !now().Add(ttl/3).Before(expires) {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err := fn(leaseCtx)
+
+	cancel()
+
+	<-renewing
+
+	if releaseErr := store.Release(name, holder); err == nil {
+		err = releaseErr
+	}
+
+	return err
+}
@@ -0,0 +1,106 @@
+package rscraper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type flakyLeaseStore struct {
+	renewals int
+	failures map[int]bool
+}
+
+func (me *flakyLeaseStore) Acquire(name, holder string, ttl time.Duration) (bool, error) {
+
+	me.renewals++
+
+	if me.failures[me.renewals] {
+		return false, errors.New("Lease store unavailable")
+	}
+
+	return true, nil
+}
+
+func (me *flakyLeaseStore) Release(name, holder string) error {
+
+	return nil
+}
+
+func TestFileLeaseStoreGuards(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "rscraper-lease")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileLeaseStore(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guard := filepath.Join(dir, "feed.lease.guard")
+
+	if err := createLeaseGuard(guard, leaseGuard{Token: "crashed", Created: now().Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if acquired, err := store.Acquire("feed", "a", time.Minute); err != nil || !acquired {
+		t.Fatalf("Expected a stale guard to be removed, got %v, %v", acquired, err)
+	}
+
+	if err := createLeaseGuard(guard, leaseGuard{Token: "live", Created: now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	removeLeaseGuard(guard, "crashed")
+
+	if held, err := readLeaseGuard(guard); err != nil || held.Token != "live" {
+		t.Fatalf("Expected a live guard to be kept, got %v, %v", held, err)
+	}
+}
+
+func TestWithLeaseToleratesOneFailedRenewal(t *testing.T) {
+
+	ttl := 30 * time.Millisecond
+
+	store := &flakyLeaseStore{failures: map[int]bool{2: true}}
+
+	err := WithLease(context.Background(), store, "feed", "a", ttl, func(ctx context.Context) error {
+
+		select {
+		case <-ctx.Done():
+			return errors.New("Lease lost after one failed renewal")
+		case <-time.After(3 * ttl):
+			return nil
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store = &flakyLeaseStore{failures: map[int]bool{2: true, 3: true}}
+
+	err = WithLease(context.Background(), store, "feed", "a", ttl, func(ctx context.Context) error {
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * ttl):
+			return errors.New("Lease kept after two failed renewals")
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
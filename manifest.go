@@ -0,0 +1,150 @@
+package rscraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// Manifest a record of how an archive was produced, so datasets built with this library can be reproduced and cited
+type Manifest struct {
+	Version    string          `json:"version"`
+	Subreddit  string          `json:"subreddit"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Oldest     time.Time       `json:"oldest"`
+	Newest     time.Time       `json:"newest"`
+	Count      int             `json:"count"`
+	Endpoints  []ManifestEntry `json:"endpoints"`
+	Files      []ManifestFile  `json:"files"`
+}
+
+// ManifestEntry an endpoint the archiver paged through and the number of items it returned
+type ManifestEntry struct {
+	Endpoint   string            `json:"endpoint"`
+	Parameters map[string]string `json:"parameters"`
+	Count      int               `json:"count"`
+	Truncated  bool              `json:"truncated"`
+}
+
+// ManifestFile an output file written from an archive
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func newManifest(subreddit string) *Manifest {
+
+	return &Manifest{
+		Version:   Version,
+		Subreddit: subreddit,
+		StartedAt: now().UTC(),
+		Endpoints: make([]ManifestEntry, 0),
+		Files:     make([]ManifestFile, 0),
+	}
+}
+
+// AddFile record an output file and its SHA-256 hash in the manifest
+func (me *Manifest) AddFile(path string) error {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+
+	if err != nil {
+		return err
+	}
+
+	me.Files = append(me.Files, ManifestFile{Path: path, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))})
+
+	return nil
+}
+
+// Write write the manifest as indented JSON
+func (me *Manifest) Write(w io.Writer) error {
+
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(me)
+}
+
+func (me *Manifest) addShard(subreddit string, shard archiveShard, count int, truncated bool) {
+
+	redditURL := getPostsURL(subreddit, shard.listingType, "", shard.topType)
+
+	me.Endpoints = append(me.Endpoints, ManifestEntry{
+		Endpoint:   redditURL.Path,
+		Parameters: flattenQuery(redditURL.Query()),
+		Count:      count,
+		Truncated:  truncated,
+	})
+}
+
+func (me *Manifest) addWindows(subreddit string, windows []BackfillWindow, count int) {
+
+	redditURL := getSearchURL(subreddit, "", "")
+
+	parameters := flattenQuery(redditURL.Query())
+
+	span := windows[0]
+
+	for _, window := range windows {
+
+		if window.Start.Before(span.Start) {
+			span.Start = window.Start
+		}
+
+		if window.End.After(span.End) {
+			span.End = window.End
+		}
+	}
+
+	parameters["q"] = span.Query()
+
+	me.Endpoints = append(me.Endpoints, ManifestEntry{Endpoint: redditURL.Path, Parameters: parameters, Count: count})
+}
+
+func (me *Manifest) observe(post Post) {
+
+	me.Count++
+
+	if me.Oldest.IsZero() || post.CreatedOn.Before(me.Oldest) {
+		me.Oldest = post.CreatedOn
+	}
+
+	if post.CreatedOn.After(me.Newest) {
+		me.Newest = post.CreatedOn
+	}
+}
+
+func (me *Manifest) finish() {
+
+	me.FinishedAt = now().UTC()
+}
+
+func flattenQuery(values map[string][]string) map[string]string {
+
+	result := make(map[string]string)
+
+	for key, value := range values {
+		if len(value) > 0 {
+			result[key] = value[0]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,143 @@
+package rscraper
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// AssetIcon a subreddit's icon
+	AssetIcon = "icon"
+
+	// AssetCommunityIcon a subreddit's redesign community icon
+	AssetCommunityIcon = "community_icon"
+
+	// AssetHeader a subreddit's old reddit header image
+	AssetHeader = "header"
+
+	// AssetBanner a subreddit's banner
+	AssetBanner = "banner"
+
+	// AssetBannerBackground a subreddit's redesign banner background
+	AssetBannerBackground = "banner_background"
+
+	// MediaModeFull download a post's media at full resolution
+	MediaModeFull = "full"
+
+	// MediaModeThumbnail download only the smallest preview of a post's media, for quick visual indexes
+	MediaModeThumbnail = "thumbnail"
+)
+
+// SubredditAssets the URLs of a subreddit's images, keyed by asset type (AssetIcon, AssetBanner, ...). Assets the subreddit does not have are omitted
+func SubredditAssets(subreddit *Subreddit) map[string]string {
+
+	assets := make(map[string]string)
+
+	candidates := map[string]string{
+		AssetIcon:             subreddit.IconImg,
+		AssetCommunityIcon:    subreddit.CommunityIcon,
+		AssetHeader:           subreddit.HeaderImg,
+		AssetBanner:           subreddit.BannerImg,
+		AssetBannerBackground: subreddit.BannerBackgroundImage,
+	}
+
+	for asset, link := range candidates {
+		if link = html.UnescapeString(link); link != "" {
+			assets[asset] = link
+		}
+	}
+
+	return assets
+}
+
+// DownloadSubredditAssets download all of a subreddit's images into a directory, returning the paths of the downloaded files. Files are named after the subreddit and asset type
+func DownloadSubredditAssets(subreddit *Subreddit, dir string) ([]string, error) {
+
+	files, err := (&MediaDownloader{}).DownloadSubredditAssets(subreddit, dir)
+
+	return mediaPaths(files), err
+}
+
+// ResolvePostMedia the URLs of a post's media in the provided media mode. Full resolution resolves to the post's linked image or its preview sources, thumbnail mode to the smallest preview of each image or the post thumbnail
+func ResolvePostMedia(post *Post, mode string) []string {
+
+	links := make([]string, 0)
+
+	images := make([]PreviewImage, 0)
+
+	if post.Preview != nil {
+		images = post.Preview.Images
+	}
+
+	if mode == MediaModeThumbnail {
+
+		for _, image := range images {
+
+			source := image.Source
+
+			if len(image.Resolutions) > 0 {
+				source = image.Resolutions[0]
+			}
+
+			links = append(links, html.UnescapeString(source.URL))
+		}
+
+		if len(links) == 0 && isMediaURL(post.Thumbnail) {
+			links = append(links, html.UnescapeString(post.Thumbnail))
+		}
+
+		return links
+	}
+
+	if isImageURL(post.URL) {
+		return append(links, html.UnescapeString(post.URL))
+	}
+
+	for _, image := range images {
+		links = append(links, html.UnescapeString(image.Source.URL))
+	}
+
+	return links
+}
+
+// DownloadPostMedia download a post's media into a directory in the provided media mode, returning the paths of the downloaded files. Files are named after the post ID, image index and mode, so a thumbnail download can later be upgraded to full resolution alongside it
+func DownloadPostMedia(post *Post, dir, mode string) ([]string, error) {
+
+	files, err := (&MediaDownloader{}).DownloadPostMedia(post, dir, mode)
+
+	return mediaPaths(files), err
+}
+
+func isMediaURL(link string) bool {
+
+	parsed, err := url.Parse(link)
+
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
+
+func isImageURL(link string) bool {
+
+	if !isMediaURL(link) {
+		return false
+	}
+
+	switch strings.ToLower(mediaExtension(link)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return true
+	}
+
+	return false
+}
+
+func mediaExtension(link string) string {
+
+	parsed, err := url.Parse(link)
+
+	if err != nil {
+		return ""
+	}
+
+	return path.Ext(parsed.Path)
+}
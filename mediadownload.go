@@ -0,0 +1,366 @@
+package rscraper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	// register decoders for perceptual hashing of downloaded images
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// MediaFile a media file handled by a MediaDownloader
+type MediaFile struct {
+	URL            string
+	Path           string
+	SHA256         string
+	PerceptualHash uint64
+	Duplicate      bool
+}
+
+// mediaPerceptualDistance the largest Hamming distance between the average hashes of two images considered the same by default
+const mediaPerceptualDistance = 5
+
+// MediaDownloader downloads media files, hashing their content and skipping content it has already stored
+type MediaDownloader struct {
+
+	// Store remembers the URLs, ETags and hashes of downloaded files. When it also implements DedupeLookup, files whose URL or ETag is already known are not fetched at all. When it also implements DedupeRange, the perceptual hashes it recorded are loaded before the first perceptual comparison, so similar images stored by earlier runs are skipped too. When nil every file is written
+	Store DedupeStore
+
+	// Perceptual deduplicate images by an average hash of their content, so re-encoded or resized copies of the same image are also skipped
+	Perceptual bool
+
+	// PerceptualDistance the largest Hamming distance between the average hashes of two images considered the same. Zero uses 5
+	PerceptualDistance int
+
+	mutex  sync.Mutex
+	images []mediaImage
+	loaded bool
+}
+
+// mediaPerceptualPrefix the prefix of the keys perceptual hashes are recorded under
+const mediaPerceptualPrefix = "ahash:"
+
+type mediaImage struct {
+	hash uint64
+	path string
+}
+
+// DownloadPostMedia download a post's media into a directory in the provided media mode. Files whose content is already in the store are not written again; their Path refers to the stored copy
+func (me *MediaDownloader) DownloadPostMedia(post *Post, dir, mode string) ([]MediaFile, error) {
+
+	files := make([]MediaFile, 0)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	for i, link := range ResolvePostMedia(post, mode) {
+
+		file, err := me.download(link, filepath.Join(dir, fmt.Sprintf("%s_%d_%s%s", post.ID, i, mode, mediaExtension(link))))
+
+		if err != nil {
+			return files, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// DownloadSubredditAssets download all of a subreddit's images into a directory. Files whose content is already in the store are not written again; their Path refers to the stored copy
+func (me *MediaDownloader) DownloadSubredditAssets(subreddit *Subreddit, dir string) ([]MediaFile, error) {
+
+	files := make([]MediaFile, 0)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	for asset, link := range SubredditAssets(subreddit) {
+
+		file, err := me.download(link, filepath.Join(dir, subreddit.Name+"_"+asset+mediaExtension(link)))
+
+		if err != nil {
+			return files, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (me *MediaDownloader) download(link, filePath string) (MediaFile, error) {
+
+	file := MediaFile{URL: link, Path: filePath}
+
+	keys := []string{"url:" + link}
+
+	if lookup, ok := me.Store.(DedupeLookup); ok {
+
+		if header, err := head(link); err == nil && header.Get("ETag") != "" {
+			keys = append(keys, "etag:"+header.Get("ETag"))
+		}
+
+		for _, key := range keys {
+
+			existing, found, err := lookup.Lookup(key)
+
+			if err != nil {
+				return file, err
+			}
+
+			if found {
+				file.Path = existing
+				file.Duplicate = true
+				return file, nil
+			}
+		}
+	}
+
+	req, err := http.NewRequest("GET", link, nil)
+
+	if err != nil {
+		return file, err
+	}
+
+	content, err := do(req)
+
+	if err != nil {
+		return file, err
+	}
+
+	hash := sha256.Sum256(content)
+
+	file.SHA256 = hex.EncodeToString(hash[:])
+
+	keys = append(keys, "sha256:"+file.SHA256)
+
+	perceptual := false
+
+	if me.Perceptual {
+		if perceptualHash, err := averageHash(content); err == nil {
+
+			file.PerceptualHash = perceptualHash
+			perceptual = true
+
+			keys = append(keys, fmt.Sprintf("%s%016x", mediaPerceptualPrefix, perceptualHash))
+
+			if err := me.loadImages(); err != nil {
+				return file, err
+			}
+
+			if existing, found := me.similarImage(perceptualHash); found {
+				file.Path = existing
+				file.Duplicate = true
+				return file, me.record(keys, existing)
+			}
+		}
+	}
+
+	if existing, found, err := me.known(keys); err != nil || found {
+
+		if found {
+			file.Path = existing
+			file.Duplicate = true
+		}
+
+		return file, err
+	}
+
+	if err := ioutil.WriteFile(filePath, content, 0644); err != nil {
+		return file, err
+	}
+
+	if me.Store != nil {
+
+		existing, duplicate, err := me.Store.Add("sha256:"+file.SHA256, filePath)
+
+		if err != nil {
+			return file, err
+		}
+
+		if duplicate && existing != filePath {
+
+			os.Remove(filePath)
+
+			file.Path = existing
+			file.Duplicate = true
+
+			return file, me.record(keys, existing)
+		}
+	}
+
+	if perceptual {
+		me.mutex.Lock()
+		me.images = append(me.images, mediaImage{hash: file.PerceptualHash, path: filePath})
+		me.mutex.Unlock()
+	}
+
+	return file, me.record(keys, filePath)
+}
+
+// known the path of a stored file recorded under any of keys, when the store supports lookups. Other stores are checked by content hash once the file is written
+func (me *MediaDownloader) known(keys []string) (string, bool, error) {
+
+	lookup, ok := me.Store.(DedupeLookup)
+
+	if !ok {
+		return "", false, nil
+	}
+
+	for _, key := range keys {
+
+		existing, found, err := lookup.Lookup(key)
+
+		if err != nil || found {
+			return existing, found, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// loadImages load the perceptual hashes recorded in the store by earlier runs, once, when the store can list them
+func (me *MediaDownloader) loadImages() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if me.loaded {
+		return nil
+	}
+
+	if ranger, ok := me.Store.(DedupeRange); ok {
+
+		err := ranger.Range(mediaPerceptualPrefix, func(key, value string) error {
+
+			if hash, err := strconv.ParseUint(strings.TrimPrefix(key, mediaPerceptualPrefix), 16, 64); err == nil {
+				me.images = append(me.images, mediaImage{hash: hash, path: value})
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	me.loaded = true
+
+	return nil
+}
+
+// similarImage the path of a downloaded image whose average hash is within the perceptual distance of hash
+func (me *MediaDownloader) similarImage(hash uint64) (string, bool) {
+
+	distance := me.PerceptualDistance
+
+	if distance <= 0 {
+		distance = mediaPerceptualDistance
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, image := range me.images {
+		if HammingDistance(image.hash, hash) <= distance {
+			return image.path, true
+		}
+	}
+
+	return "", false
+}
+
+// record remember every key of a file once it is stored at path, so later copies are skipped. Keys already recorded keep their path
+func (me *MediaDownloader) record(keys []string, path string) error {
+
+	if me.Store == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		if _, _, err := me.Store.Add(key, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// averageHash a 64 bit perceptual hash of an image: the image is reduced to 8x8 greyscale and each bit records whether a cell is brighter than the mean
+func averageHash(content []byte) (uint64, error) {
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+
+	if err != nil {
+		return 0, err
+	}
+
+	bounds := img.Bounds()
+
+	if bounds.Dx() < 8 || bounds.Dy() < 8 {
+		return 0, fmt.Errorf("Image is too small to hash (%dx%d)", bounds.Dx(), bounds.Dy())
+	}
+
+	var cells [64]float64
+
+	total := 0.0
+
+	for cell := range cells {
+
+		x0 := bounds.Min.X + (cell%8)*bounds.Dx()/8
+		x1 := bounds.Min.X + (cell%8+1)*bounds.Dx()/8
+		y0 := bounds.Min.Y + (cell/8)*bounds.Dy()/8
+		y1 := bounds.Min.Y + (cell/8+1)*bounds.Dy()/8
+
+		sum := 0.0
+
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			}
+		}
+
+		cells[cell] = sum / float64((x1-x0)*(y1-y0))
+		total += cells[cell]
+	}
+
+	mean := total / 64
+
+	var result uint64
+
+	for cell, value := range cells {
+		if value > mean {
+			result |= 1 << uint(cell)
+		}
+	}
+
+	return result, nil
+}
+
+func mediaPaths(files []MediaFile) []string {
+
+	paths := make([]string, 0, len(files))
+
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	return paths
+}
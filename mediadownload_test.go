@@ -0,0 +1,134 @@
+package rscraper
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testImage(t *testing.T, shade uint8) []byte {
+
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			if x < 8 {
+				img.SetGray(x, y, color.Gray{Y: shade})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestMediaDownloaderSkipsKnownMedia(t *testing.T) {
+
+	images := map[string][]byte{"/a.png": testImage(t, 0), "/b.png": testImage(t, 0), "/c.png": testImage(t, 10)}
+
+	gets := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+
+		if r.URL.Path == "/b.png" {
+			w.Header().Set("ETag", `"/a.png"`)
+		}
+
+		if r.Method == "GET" {
+			gets++
+			w.Write(images[r.URL.Path])
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "rscraper")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	downloader := &MediaDownloader{Store: NewMemoryDedupeStore(0), Perceptual: true}
+
+	first, err := downloader.download(server.URL+"/a.png", filepath.Join(dir, "a.png"))
+
+	if err != nil || first.Duplicate {
+		t.Fatalf("First download: %+v, %v", first, err)
+	}
+
+	for _, name := range []string{"a.png", "b.png"} {
+
+		file, err := downloader.download(server.URL+"/"+name, filepath.Join(dir, "x_"+name))
+
+		if err != nil || !file.Duplicate || file.Path != first.Path {
+			t.Fatalf("Download of %s: %+v, %v", name, file, err)
+		}
+	}
+
+	if gets != 1 {
+		t.Fatalf("Fetched %d bodies, want 1", gets)
+	}
+
+	similar, err := downloader.download(server.URL+"/c.png", filepath.Join(dir, "c.png"))
+
+	if err != nil || !similar.Duplicate || similar.Path != first.Path {
+		t.Fatalf("Similar image: %+v, %v", similar, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "c.png")); !os.IsNotExist(err) {
+		t.Fatalf("Similar image was written: %v", err)
+	}
+}
+
+func TestMediaDownloaderLoadsStoredImages(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testImage(t, 10))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "rscraper")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	store := NewMemoryDedupeStore(0)
+
+	hash, err := averageHash(testImage(t, 0))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.Add(fmt.Sprintf("ahash:%016x", hash), "stored.png")
+	store.Add("ahash:not-a-hash", "other.png")
+
+	downloader := &MediaDownloader{Store: store, Perceptual: true}
+
+	file, err := downloader.download(server.URL+"/c.png", filepath.Join(dir, "c.png"))
+
+	if err != nil || !file.Duplicate || file.Path != "stored.png" {
+		t.Fatalf("Similar image: %+v, %v", file, err)
+	}
+}
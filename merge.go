@@ -0,0 +1,270 @@
+package rscraper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MergeConflict a field two records of the same item disagree on
+type MergeConflict struct {
+	Fullname  string      `json:"fullname"`
+	Field     string      `json:"field"`
+	Kept      interface{} `json:"kept"`
+	Discarded interface{} `json:"discarded"`
+}
+
+// Merger combines posts and comments from several sources, such as the live API, dumps and data exports, into one record per item keyed by fullname. The most recently fetched record of an item is kept, or the most complete one when neither records when it was fetched, and fields it lacks are filled in from the other records. Merger is a Sink, so ImportDump and ExportStore can write to it directly
+type Merger struct {
+	mutex     sync.Mutex
+	items     map[string]interface{}
+	conflicts []MergeConflict
+}
+
+// NewMerger create a new empty merger
+func NewMerger() *Merger {
+
+	return &Merger{items: make(map[string]interface{}), conflicts: make([]MergeConflict, 0)}
+}
+
+// Write merge each Post, Comment, Thread or Snapshot item
+func (me *Merger) Write(items ...interface{}) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, item := range items {
+
+		switch value := item.(type) {
+		case Post:
+			me.merge(value.Fullname(), &value)
+		case *Post:
+			copied := *value
+			me.merge(copied.Fullname(), &copied)
+		case Comment:
+			me.merge(value.Fullname(), &value)
+		case *Comment:
+			copied := *value
+			me.merge(copied.Fullname(), &copied)
+		case Thread:
+			me.mergeThread(&value)
+		case *Thread:
+			me.mergeThread(value)
+		case Snapshot:
+			me.mergeSnapshot(&value)
+		case *Snapshot:
+			me.mergeSnapshot(value)
+		default:
+			return fmt.Errorf("Cannot merge item of type %T", item)
+		}
+	}
+
+	return nil
+}
+
+// Flush does nothing, merged items are kept in memory
+func (me *Merger) Flush() error {
+
+	return nil
+}
+
+// Posts the merged posts, newest first
+func (me *Merger) Posts() []Post {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	posts := make([]Post, 0)
+
+	for _, item := range me.items {
+		if post, ok := item.(*Post); ok {
+			posts = append(posts, *post)
+		}
+	}
+
+	sortPosts(posts)
+
+	return posts
+}
+
+// Comments the merged comments, newest first
+func (me *Merger) Comments() []Comment {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	comments := make([]Comment, 0)
+
+	for _, item := range me.items {
+		if comment, ok := item.(*Comment); ok {
+			comments = append(comments, *comment)
+		}
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedUTC > comments[j].CreatedUTC
+	})
+
+	return comments
+}
+
+// Conflicts every field on which merged records disagreed, in the order they were found
+func (me *Merger) Conflicts() []MergeConflict {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return append([]MergeConflict(nil), me.conflicts...)
+}
+
+func (me *Merger) mergeThread(thread *Thread) {
+
+	post := thread.Post
+
+	me.merge(post.Fullname(), &post)
+
+	if thread.Comments == nil {
+		return
+	}
+
+	for _, comment := range thread.Comments.Flatten() {
+
+		copied := comment
+
+		me.merge(copied.Fullname(), &copied)
+	}
+}
+
+func (me *Merger) mergeSnapshot(snapshot *Snapshot) {
+
+	if snapshot.Post != nil {
+		copied := *snapshot.Post
+		me.merge(copied.Fullname(), &copied)
+	}
+
+	if snapshot.Comment != nil {
+		copied := *snapshot.Comment
+		me.merge(copied.Fullname(), &copied)
+	}
+}
+
+// merge combine a record, a pointer to a Post or Comment owned by the merger, with the record already held for its fullname
+func (me *Merger) merge(fullname string, record interface{}) {
+
+	existing, ok := me.items[fullname]
+
+	if !ok {
+		me.items[fullname] = record
+		return
+	}
+
+	kept, discarded := existing, record
+
+	if preferRecord(record, existing) {
+		kept, discarded = record, existing
+	}
+
+	keptValue := reflect.ValueOf(kept).Elem()
+	discardedValue := reflect.ValueOf(discarded).Elem()
+
+	for i := 0; i < keptValue.NumField(); i++ {
+
+		field := keptValue.Type().Field(i)
+
+		if field.Name == "Provenance" || field.Name == "Replies" || field.Name == "RepliesAfter" {
+			continue
+		}
+
+		keptField, discardedField := keptValue.Field(i), discardedValue.Field(i)
+
+		switch {
+		case isZeroValue(discardedField):
+		case isZeroValue(keptField):
+			keptField.Set(discardedField)
+		case !mergeValuesEqual(keptField, discardedField):
+			me.conflicts = append(me.conflicts, MergeConflict{Fullname: fullname, Field: mergeFieldName(field), Kept: keptField.Interface(), Discarded: discardedField.Interface()})
+		}
+	}
+
+	me.items[fullname] = kept
+}
+
+// preferRecord whether a new record should be kept over the existing record of the same item
+func preferRecord(record, existing interface{}) bool {
+
+	recordFetched, existingFetched := fetchedAt(record), fetchedAt(existing)
+
+	if !recordFetched.Equal(existingFetched) {
+		return recordFetched.After(existingFetched)
+	}
+
+	return completeness(record) >= completeness(existing)
+}
+
+func fetchedAt(record interface{}) time.Time {
+
+	var provenance *Provenance
+
+	switch value := record.(type) {
+	case *Post:
+		provenance = value.Provenance
+	case *Comment:
+		provenance = value.Provenance
+	}
+
+	if provenance == nil {
+		return time.Time{}
+	}
+
+	return provenance.FetchedAt
+}
+
+// completeness the number of fields of a record that are set
+func completeness(record interface{}) int {
+
+	value := reflect.ValueOf(record).Elem()
+
+	count := 0
+
+	for i := 0; i < value.NumField(); i++ {
+		if !isZeroValue(value.Field(i)) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func isZeroValue(value reflect.Value) bool {
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// mergeValuesEqual compare field values, treating times in different locations as equal when they are the same instant
+func mergeValuesEqual(a, b reflect.Value) bool {
+
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Equal(b.Interface().(time.Time))
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func mergeFieldName(field reflect.StructField) string {
+
+	if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+		return tag
+	}
+
+	return field.Name
+}
@@ -0,0 +1,50 @@
+package rscraper
+
+import "math"
+
+// EstimatedUpvotes estimate the number of upvotes a post received from its score and upvote ratio.
+//
+// With score s = up - down and ratio r = up / (up + down), up = r * s / (2r - 1). When the ratio is exactly 0.5 the votes cannot be recovered and 0 is returned. Reddit fuzzes both inputs, so the result is an approximation
+func (me *Post) EstimatedUpvotes() int {
+
+	if me.UpvoteRatio <= 0 || me.UpvoteRatio == 0.5 {
+		return 0
+	}
+
+	upvotes := me.UpvoteRatio * float64(me.Score) / (2*me.UpvoteRatio - 1)
+
+	if upvotes < 0 {
+		return 0
+	}
+
+	return int(math.Round(upvotes))
+}
+
+// EstimatedDownvotes estimate the number of downvotes a post received, as EstimatedUpvotes() - Score
+func (me *Post) EstimatedDownvotes() int {
+
+	downvotes := me.EstimatedUpvotes() - me.Score
+
+	if downvotes < 0 {
+		return 0
+	}
+
+	return downvotes
+}
+
+// Controversy a measure of how divided voting on a post is, using reddit's own controversy formula on the estimated votes.
+//
+// With magnitude m = up + down and balance b = min(up, down) / max(up, down), controversy = m ^ b. A post with no downvotes or no upvotes has a controversy of 0
+func (me *Post) Controversy() float64 {
+
+	upvotes := float64(me.EstimatedUpvotes())
+	downvotes := float64(me.EstimatedDownvotes())
+
+	if upvotes <= 0 || downvotes <= 0 {
+		return 0
+	}
+
+	balance := math.Min(upvotes, downvotes) / math.Max(upvotes, downvotes)
+
+	return math.Pow(upvotes+downvotes, balance)
+}
@@ -0,0 +1,43 @@
+package rscraper
+
+// CopyStore copy every snapshot from one store to another, preserving item IDs and when each snapshot was saved. Returns the number of snapshots copied
+func CopyStore(dst, src Store) (int, error) {
+
+	copied := 0
+
+	err := src.Snapshots(func(snapshot Snapshot) error {
+
+		if err := dst.SaveSnapshot(snapshot); err != nil {
+			return err
+		}
+
+		copied++
+
+		return nil
+	})
+
+	return copied, err
+}
+
+// ExportStore write every snapshot in a store to a sink, e.g. a JSONSink, and flush it. Returns the number of snapshots exported
+func ExportStore(sink Sink, src Store) (int, error) {
+
+	exported := 0
+
+	err := src.Snapshots(func(snapshot Snapshot) error {
+
+		if err := sink.Write(snapshot); err != nil {
+			return err
+		}
+
+		exported++
+
+		return nil
+	})
+
+	if err != nil {
+		return exported, err
+	}
+
+	return exported, sink.Flush()
+}
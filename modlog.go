@@ -0,0 +1,142 @@
+package rscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const apiObjectTypeModAction = "modaction"
+
+// ModAction an action taken by a moderator, as recorded in a subreddit's moderation log
+type ModAction struct {
+	ID              string  `json:"id"`
+	Subreddit       string  `json:"subreddit"`
+	Moderator       string  `json:"mod"`
+	Action          string  `json:"action"`
+	Details         string  `json:"details"`
+	Description     string  `json:"description"`
+	TargetFullname  string  `json:"target_fullname"`
+	TargetAuthor    string  `json:"target_author"`
+	TargetPermaLink string  `json:"target_permalink"`
+	TargetTitle     string  `json:"target_title"`
+	TargetBody      string  `json:"target_body"`
+	CreatedUTC      float64 `json:"created_utc"`
+	CreatedOn       time.Time
+}
+
+// GetModLog retrieves the most recent entries of a subreddit's moderation log, newest first. Requires an access token for a moderator account, see SetAccessToken
+func GetModLog(subreddit, after string) ([]ModAction, string, error) {
+
+	actions := make([]ModAction, 0)
+
+	redditURL := getModLogURL(subreddit, after)
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, "", subredditError(subreddit, err)
+	}
+
+	list, err := extractListing(object)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, child := range list.Children {
+
+		action, err := extractModAction(&child)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		actions = append(actions, *action)
+	}
+
+	return actions, list.After, nil
+}
+
+// StreamModLog poll a subreddit's moderation log until the context is cancelled, emitting each new action once, oldest first. Errors do not stop the stream; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise. Requires an access token for a moderator account, see SetAccessToken
+func StreamModLog(ctx context.Context, interval time.Duration, subreddit string) (<-chan ModAction, <-chan error) {
+
+	actions := make(chan ModAction)
+	errs := make(chan error, 1)
+
+	interval = politeInterval(interval, subreddit)
+
+	go func() {
+
+		defer close(actions)
+		defer close(errs)
+
+		seen := newSeenSet(streamSeenLimit)
+
+		for {
+			page, _, err := GetModLog(subreddit, "")
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			for i := len(page) - 1; i >= 0; i-- {
+
+				if !seen.add(page[i].ID) {
+					continue
+				}
+
+				select {
+				case actions <- page[i]:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return actions, errs
+}
+
+func getModLogURL(subreddit, after string) *url.URL {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/r/%s/about/log.json", subreddit)
+
+	if after != "" {
+		q := redditURL.Query()
+
+		q.Set("after", after)
+
+		redditURL.RawQuery = q.Encode()
+	}
+
+	return redditURL
+}
+
+func extractModAction(object *apiObject) (*ModAction, error) {
+
+	if object == nil || object.Type != apiObjectTypeModAction {
+		return nil, errors.New("Provided API Object is not a Mod Action")
+	}
+
+	var result ModAction
+
+	err := unmarshal(object.Data, &result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.CreatedOn = redditTime(result.CreatedUTC)
+	return &result, err
+}
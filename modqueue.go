@@ -0,0 +1,217 @@
+package rscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// ModQueueReports items that have been reported by users or moderators
+	ModQueueReports = "reports"
+
+	// ModQueueAll items awaiting moderator review, including reported and spam-filtered items
+	ModQueueAll = "modqueue"
+)
+
+// Report a single report reason given for a reported post or comment
+type Report struct {
+	Reason    string
+	Count     int
+	Moderator string
+}
+
+// ReportedItem a post or comment awaiting moderator review. Exactly one of Post or Comment is set
+type ReportedItem struct {
+	Post    *Post
+	Comment *Comment
+	Reports []Report
+}
+
+type reportFields struct {
+	UserReports [][]interface{} `json:"user_reports"`
+	ModReports  [][]interface{} `json:"mod_reports"`
+}
+
+// ID the fullname of the post or comment
+func (me *ReportedItem) ID() string {
+
+	if me.Post != nil {
+		return me.Post.Fullname()
+	}
+
+	return me.Comment.Fullname()
+}
+
+// ReportCount the total number of reports made against the item
+func (me *ReportedItem) ReportCount() int {
+
+	count := 0
+
+	for _, report := range me.Reports {
+		count += report.Count
+	}
+
+	return count
+}
+
+// GetModQueue retrieves the items in one of a subreddit's moderation queues (ModQueueReports or ModQueueAll), newest first. Requires an access token for a moderator account, see SetAccessToken
+func GetModQueue(subreddit, queue, after string) ([]ReportedItem, string, error) {
+
+	items := make([]ReportedItem, 0)
+
+	redditURL := getModQueueURL(subreddit, queue, after)
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, "", subredditError(subreddit, err)
+	}
+
+	list, err := extractListing(object)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	provenance := newProvenance(redditURL.String(), 0, false)
+
+	for _, child := range list.Children {
+
+		item, err := extractReportedItem(&child)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if (item.Post != nil && !guardrailsAllowPost(item.Post)) || (item.Comment != nil && !guardrailsAllowComment(item.Comment)) {
+			continue
+		}
+
+		if item.Post != nil {
+			item.Post.Provenance = provenance.copy()
+		} else {
+			item.Comment.Provenance = provenance.copy()
+		}
+
+		items = append(items, *item)
+	}
+
+	return items, list.After, nil
+}
+
+// StreamModQueue poll one of a subreddit's moderation queues until the context is cancelled, emitting each newly reported item, oldest first. An item is emitted again when it receives further reports. Errors do not stop the stream; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise. Requires an access token for a moderator account, see SetAccessToken
+func StreamModQueue(ctx context.Context, interval time.Duration, subreddit, queue string) (<-chan ReportedItem, <-chan error) {
+
+	items := make(chan ReportedItem)
+	errs := make(chan error, 1)
+
+	interval = politeInterval(interval, subreddit)
+
+	go func() {
+
+		defer close(items)
+		defer close(errs)
+
+		seen := newSeenSet(streamSeenLimit)
+
+		for {
+			page, _, err := GetModQueue(subreddit, queue, "")
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			for i := len(page) - 1; i >= 0; i-- {
+
+				if !seen.add(fmt.Sprintf("%s:%d", page[i].ID(), page[i].ReportCount())) {
+					continue
+				}
+
+				select {
+				case items <- page[i]:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return items, errs
+}
+
+func getModQueueURL(subreddit, queue, after string) *url.URL {
+
+	redditURL := getBaseURL()
+
+	if queue != ModQueueReports {
+		queue = ModQueueAll
+	}
+
+	redditURL.Path = fmt.Sprintf("/r/%s/about/%s.json", subreddit, queue)
+
+	if after != "" {
+		q := redditURL.Query()
+
+		q.Set("after", after)
+
+		redditURL.RawQuery = q.Encode()
+	}
+
+	return redditURL
+}
+
+func extractReportedItem(object *apiObject) (*ReportedItem, error) {
+
+	var result ReportedItem
+
+	if post, err := extractPost(object); err == nil {
+		result.Post = post
+	} else if comment, err := extractComment(object); err == nil {
+		result.Comment = comment
+	} else {
+		return nil, errors.New("API Object is not a Post or Comment")
+	}
+
+	var fields reportFields
+
+	if err := unmarshal(object.Data, &fields); err != nil {
+		return nil, err
+	}
+
+	result.Reports = make([]Report, 0)
+
+	for _, report := range fields.UserReports {
+
+		if len(report) < 2 {
+			continue
+		}
+
+		reason, _ := report[0].(string)
+		count, _ := report[1].(float64)
+
+		result.Reports = append(result.Reports, Report{Reason: reason, Count: int(count)})
+	}
+
+	for _, report := range fields.ModReports {
+
+		if len(report) < 2 {
+			continue
+		}
+
+		reason, _ := report[0].(string)
+		moderator, _ := report[1].(string)
+
+		result.Reports = append(result.Reports, Report{Reason: reason, Count: 1, Moderator: moderator})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,286 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FromPosts adapt a channel of posts, such as the output of StreamPosts, into a pipeline stage
+func FromPosts(ctx context.Context, posts <-chan Post) <-chan interface{} {
+
+	out := make(chan interface{})
+
+	go func() {
+
+		defer close(out)
+
+		for {
+			select {
+			case post, ok := <-posts:
+				if !ok || !send(ctx, out, post) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// FromComments adapt a channel of comments into a pipeline stage
+func FromComments(ctx context.Context, comments <-chan Comment) <-chan interface{} {
+
+	out := make(chan interface{})
+
+	go func() {
+
+		defer close(out)
+
+		for {
+			select {
+			case comment, ok := <-comments:
+				if !ok || !send(ctx, out, comment) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Filter pass on only the items the filter accepts
+func Filter(ctx context.Context, in <-chan interface{}, filter FilterFunc) <-chan interface{} {
+
+	out := make(chan interface{})
+
+	go func() {
+
+		defer close(out)
+
+		for item, ok := receive(ctx, in); ok; item, ok = receive(ctx, in) {
+			if filter(item) && !send(ctx, out, item) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Map pass on the result of applying fn to each item. Items for which fn returns nil are dropped
+func Map(ctx context.Context, in <-chan interface{}, fn func(item interface{}) interface{}) <-chan interface{} {
+
+	out := make(chan interface{})
+
+	go func() {
+
+		defer close(out)
+
+		for item, ok := receive(ctx, in); ok; item, ok = receive(ctx, in) {
+			if result := fn(item); result != nil && !send(ctx, out, result) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Dedupe pass on only the first item with each key. A nil key function uses ItemKey. The most recent limit keys are remembered, or 2000 keys if limit is not positive
+func Dedupe(ctx context.Context, in <-chan interface{}, key func(item interface{}) string, limit int) <-chan interface{} {
+
+	out := make(chan interface{})
+
+	if key == nil {
+		key = ItemKey
+	}
+
+	if limit <= 0 {
+		limit = streamSeenLimit
+	}
+
+	go func() {
+
+		defer close(out)
+
+		seen := newSeenSet(limit)
+
+		for item, ok := receive(ctx, in); ok; item, ok = receive(ctx, in) {
+			if seen.add(key(item)) && !send(ctx, out, item) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Batch group items into batches of up to size items. A partial batch is passed on once interval has elapsed since its first item, if interval is positive, and when the input closes
+func Batch(ctx context.Context, in <-chan interface{}, size int, interval time.Duration) <-chan []interface{} {
+
+	out := make(chan []interface{})
+
+	if size < 1 {
+		size = 1
+	}
+
+	go func() {
+
+		defer close(out)
+
+		batch := make([]interface{}, 0, size)
+
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+
+			if len(batch) == 0 {
+				return true
+			}
+
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return false
+			}
+
+			batch = make([]interface{}, 0, size)
+			timeout = nil
+
+			return true
+		}
+
+		for {
+			select {
+			case item, ok := <-in:
+
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, item)
+
+				if len(batch) == 1 && interval > 0 {
+					timeout = after(interval)
+				}
+
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Tee copy every item to n outputs. Each item is delivered to every output before the next item is read, so all outputs must be consumed
+func Tee(ctx context.Context, in <-chan interface{}, n int) []<-chan interface{} {
+
+	outs := make([]chan interface{}, n)
+	results := make([]<-chan interface{}, n)
+
+	for i := range outs {
+		outs[i] = make(chan interface{})
+		results[i] = outs[i]
+	}
+
+	go func() {
+
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for item, ok := receive(ctx, in); ok; item, ok = receive(ctx, in) {
+			for _, out := range outs {
+				if !send(ctx, out, item) {
+					return
+				}
+			}
+		}
+	}()
+
+	return results
+}
+
+// Drain write every item to a sink until the input closes or the context is cancelled, then flush the sink
+func Drain(ctx context.Context, in <-chan interface{}, sink Sink) error {
+
+	for {
+		select {
+		case item, ok := <-in:
+
+			if !ok {
+				return sink.Flush()
+			}
+
+			if err := sink.Write(item); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+
+			if err := sink.Flush(); err != nil {
+				return err
+			}
+
+			return ctx.Err()
+		}
+	}
+}
+
+// ItemKey a key identifying a scraped item: the fullname of posts and comments, the post fullname of threads, and the formatted value of anything else
+func ItemKey(item interface{}) string {
+
+	switch value := item.(type) {
+	case Post:
+		return value.Fullname()
+	case *Post:
+		return value.Fullname()
+	case Comment:
+		return value.Fullname()
+	case *Comment:
+		return value.Fullname()
+	case Thread:
+		return value.Post.Fullname()
+	case *Thread:
+		return value.Post.Fullname()
+	}
+
+	return fmt.Sprintf("%v", item)
+}
+
+func send(ctx context.Context, out chan<- interface{}, item interface{}) bool {
+
+	select {
+	case out <- item:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receive the next item from a stage's input, reporting false once the input closes or the context is cancelled
+func receive(ctx context.Context, in <-chan interface{}) (interface{}, bool) {
+
+	select {
+	case item, ok := <-in:
+		return item, ok
+	case <-ctx.Done():
+		return nil, false
+	}
+}
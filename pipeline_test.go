@@ -0,0 +1,36 @@
+package rscraper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPipelineStagesStopWhenCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan interface{})
+
+	outs := []<-chan interface{}{
+		Filter(ctx, in, func(item interface{}) bool { return true }),
+		Map(ctx, in, func(item interface{}) interface{} { return item }),
+		Dedupe(ctx, in, nil, 0),
+		Tee(ctx, in, 1)[0],
+		Route(ctx, in, "a")[0],
+		FromPosts(ctx, make(chan Post)),
+	}
+
+	cancel()
+
+	for i, out := range outs {
+		select {
+		case _, ok := <-out:
+			if ok {
+				t.Errorf("Stage %d passed on an item after cancellation", i)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Stage %d still running after cancellation while its input stayed open", i)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package rscraper
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Politeness limits applied to a single subreddit, independent of the package-wide guardrails
+type Politeness struct {
+
+	// MinInterval the shortest interval at which streams poll the subreddit
+	MinInterval time.Duration
+
+	// MaxConcurrency the maximum number of listings of the subreddit fetched in parallel, e.g. by archiver shards. Zero means no limit
+	MaxConcurrency int
+}
+
+type politenessEntry struct {
+	politeness Politeness
+	slots      chan struct{}
+}
+
+var (
+	politenessMutex sync.Mutex
+	politeness      = make(map[string]*politenessEntry)
+)
+
+// SetSubredditPoliteness override the politeness limits for a subreddit, e.g. to poll small communities less often. A zero Politeness removes the override
+func SetSubredditPoliteness(subreddit string, p Politeness) {
+
+	politenessMutex.Lock()
+	defer politenessMutex.Unlock()
+
+	key := strings.ToLower(subreddit)
+
+	if p == (Politeness{}) {
+		delete(politeness, key)
+		return
+	}
+
+	entry := &politenessEntry{politeness: p}
+
+	if p.MaxConcurrency > 0 {
+		entry.slots = make(chan struct{}, p.MaxConcurrency)
+	}
+
+	politeness[key] = entry
+}
+
+// politeInterval the polling interval to use for a set of subreddits, raised to the slowest override among them
+func politeInterval(interval time.Duration, subreddits ...string) time.Duration {
+
+	politenessMutex.Lock()
+	defer politenessMutex.Unlock()
+
+	for _, subreddit := range subreddits {
+		if entry, ok := politeness[strings.ToLower(subreddit)]; ok && entry.politeness.MinInterval > interval {
+			interval = entry.politeness.MinInterval
+		}
+	}
+
+	return interval
+}
+
+// acquireSubreddit wait for a free concurrency slot for the subreddit, returning a function releasing it
+func acquireSubreddit(subreddit string) func() {
+
+	politenessMutex.Lock()
+
+	entry, ok := politeness[strings.ToLower(subreddit)]
+
+	politenessMutex.Unlock()
+
+	if !ok || entry.slots == nil {
+		return func() {}
+	}
+
+	entry.slots <- struct{}{}
+
+	return func() {
+		<-entry.slots
+	}
+}
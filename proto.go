@@ -0,0 +1,526 @@
+package rscraper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Protocol buffer wire types used by the messages in proto/rscraper.proto
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+)
+
+// protoWriter appends protocol buffer fields to a message, leaving out fields with default values as proto3 does
+type protoWriter struct {
+	buf []byte
+}
+
+func (me *protoWriter) key(field, wireType int) {
+
+	me.buf = appendProtoVarint(me.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (me *protoWriter) string(field int, value string) {
+
+	if value == "" {
+		return
+	}
+
+	me.key(field, protoWireBytes)
+	me.buf = appendProtoVarint(me.buf, uint64(len(value)))
+	me.buf = append(me.buf, value...)
+}
+
+func (me *protoWriter) int(field int, value int64) {
+
+	if value == 0 {
+		return
+	}
+
+	me.key(field, protoWireVarint)
+	me.buf = appendProtoVarint(me.buf, uint64(value))
+}
+
+func (me *protoWriter) bool(field int, value bool) {
+
+	if value {
+		me.key(field, protoWireVarint)
+		me.buf = append(me.buf, 1)
+	}
+}
+
+func (me *protoWriter) double(field int, value float64) {
+
+	if value == 0 {
+		return
+	}
+
+	me.key(field, protoWireFixed64)
+	me.buf = appendProtoFixed64(me.buf, math.Float64bits(value))
+}
+
+func (me *protoWriter) message(field int, message []byte) {
+
+	me.key(field, protoWireBytes)
+	me.buf = appendProtoVarint(me.buf, uint64(len(message)))
+	me.buf = append(me.buf, message...)
+}
+
+func appendProtoVarint(dst []byte, value uint64) []byte {
+
+	var buf [binary.MaxVarintLen64]byte
+
+	return append(dst, buf[:binary.PutUvarint(buf[:], value)]...)
+}
+
+func appendProtoFixed64(dst []byte, value uint64) []byte {
+
+	var buf [8]byte
+
+	binary.LittleEndian.PutUint64(buf[:], value)
+
+	return append(dst, buf[:]...)
+}
+
+// protoField a field read from a message. Varint and fixed64 fields are held in number, length-delimited fields in bytes
+type protoField struct {
+	number int
+	varint uint64
+	bytes  []byte
+}
+
+func (me *protoField) string() string {
+
+	return string(me.bytes)
+}
+
+func (me *protoField) int() int {
+
+	return int(int32(me.varint))
+}
+
+func (me *protoField) double() float64 {
+
+	return math.Float64frombits(me.varint)
+}
+
+// readProto call fn with each field of a message. Fields of wire types the messages do not use are rejected
+func readProto(data []byte, fn func(field *protoField) error) error {
+
+	for len(data) > 0 {
+
+		key, n := binary.Uvarint(data)
+
+		if n <= 0 {
+			return errors.New("Invalid protocol buffer field key")
+		}
+
+		data = data[n:]
+
+		field := protoField{number: int(key >> 3)}
+
+		switch key & 7 {
+		case protoWireVarint:
+
+			field.varint, n = binary.Uvarint(data)
+
+			if n <= 0 {
+				return errors.New("Invalid protocol buffer varint")
+			}
+
+			data = data[n:]
+		case protoWireFixed64:
+
+			if len(data) < 8 {
+				return errors.New("Truncated protocol buffer fixed64")
+			}
+
+			field.varint = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case protoWireBytes:
+
+			length, n := binary.Uvarint(data)
+
+			if n <= 0 || uint64(len(data)-n) < length {
+				return errors.New("Truncated protocol buffer field")
+			}
+
+			field.bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("Unsupported protocol buffer wire type %d", key&7)
+		}
+
+		if err := fn(&field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func protoTime(t time.Time) int64 {
+
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// MarshalProto encode the post as a Post message of proto/rscraper.proto
+func (me *Post) MarshalProto() []byte {
+
+	var w protoWriter
+
+	w.string(1, me.ID)
+	w.string(2, me.SubredditID)
+	w.string(3, me.Subreddit)
+	w.string(4, me.SubredditType)
+	w.string(5, me.Author)
+	w.string(6, me.LinkFlairText)
+	w.string(7, me.LinkFlairCSS)
+	w.string(8, me.LinkFlairTemplateID)
+	w.string(9, me.AuthorFlairText)
+	w.string(10, me.AuthorFlairCSS)
+	w.string(11, me.Title)
+	w.string(12, me.URL)
+	w.string(13, me.PermaLink)
+	w.double(14, me.CreatedUTC)
+	w.int(15, int64(me.Gilded))
+	w.int(16, int64(me.Score))
+	w.double(17, me.UpvoteRatio)
+	w.int(18, int64(me.UpVotes))
+	w.int(19, int64(me.DownVotes))
+	w.int(20, int64(me.NumComments))
+	w.string(21, me.Text)
+	w.string(22, me.TextHTML)
+	w.string(23, me.Thumbnail)
+	w.bool(24, me.IsSelf)
+	w.bool(25, me.Over18)
+	w.bool(26, me.Locked)
+	w.bool(27, me.Archived)
+	w.int(28, int64(me.TotalAwards))
+	w.int(29, protoTime(me.EditedOn))
+	w.int(30, protoTime(me.BannedOn))
+	w.string(31, me.AuthorStatus)
+
+	return w.buf
+}
+
+// UnmarshalProto decode a Post message of proto/rscraper.proto into the post
+func (me *Post) UnmarshalProto(data []byte) error {
+
+	*me = Post{}
+
+	err := readProto(data, func(f *protoField) error {
+
+		switch f.number {
+		case 1:
+			me.ID = f.string()
+		case 2:
+			me.SubredditID = f.string()
+		case 3:
+			me.Subreddit = f.string()
+		case 4:
+			me.SubredditType = f.string()
+		case 5:
+			me.Author = f.string()
+		case 6:
+			me.LinkFlairText = f.string()
+		case 7:
+			me.LinkFlairCSS = f.string()
+		case 8:
+			me.LinkFlairTemplateID = f.string()
+		case 9:
+			me.AuthorFlairText = f.string()
+		case 10:
+			me.AuthorFlairCSS = f.string()
+		case 11:
+			me.Title = f.string()
+		case 12:
+			me.URL = f.string()
+		case 13:
+			me.PermaLink = f.string()
+		case 14:
+			me.CreatedUTC = f.double()
+		case 15:
+			me.Gilded = f.int()
+		case 16:
+			me.Score = f.int()
+		case 17:
+			me.UpvoteRatio = f.double()
+		case 18:
+			me.UpVotes = f.int()
+		case 19:
+			me.DownVotes = f.int()
+		case 20:
+			me.NumComments = f.int()
+		case 21:
+			me.Text = f.string()
+		case 22:
+			me.TextHTML = f.string()
+		case 23:
+			me.Thumbnail = f.string()
+		case 24:
+			me.IsSelf = f.varint != 0
+		case 25:
+			me.Over18 = f.varint != 0
+		case 26:
+			me.Locked = f.varint != 0
+		case 27:
+			me.Archived = f.varint != 0
+		case 28:
+			me.TotalAwards = f.int()
+		case 29:
+			me.EditedOn = redditTime(float64(int64(f.varint)))
+		case 30:
+			me.BannedOn = redditTime(float64(int64(f.varint)))
+		case 31:
+			me.AuthorStatus = f.string()
+		}
+
+		return nil
+	})
+
+	me.CreatedOn = redditTime(me.CreatedUTC)
+
+	return err
+}
+
+// MarshalProto encode the comment as a Comment message of proto/rscraper.proto
+func (me *Comment) MarshalProto() []byte {
+
+	var w protoWriter
+
+	w.string(1, me.ID)
+	w.string(2, me.PostID)
+	w.string(3, me.ParentID)
+	w.string(4, me.Subreddit)
+	w.string(5, me.Author)
+	w.string(6, me.AuthorFlairText)
+	w.string(7, me.AuthorFlairCSS)
+	w.string(8, me.PermaLink)
+	w.double(9, me.CreatedUTC)
+	w.int(10, int64(me.Gilded))
+	w.int(11, int64(me.Score))
+	w.int(12, int64(me.UpVotes))
+	w.int(13, int64(me.DownVotes))
+	w.string(14, me.Body)
+	w.string(15, me.BodyHTML)
+	w.int(16, protoTime(me.EditedOn))
+	w.int(17, protoTime(me.BannedOn))
+	w.string(18, me.AuthorStatus)
+
+	return w.buf
+}
+
+// UnmarshalProto decode a Comment message of proto/rscraper.proto into the comment
+func (me *Comment) UnmarshalProto(data []byte) error {
+
+	*me = Comment{}
+
+	err := readProto(data, func(f *protoField) error {
+
+		switch f.number {
+		case 1:
+			me.ID = f.string()
+		case 2:
+			me.PostID = f.string()
+		case 3:
+			me.ParentID = f.string()
+		case 4:
+			me.Subreddit = f.string()
+		case 5:
+			me.Author = f.string()
+		case 6:
+			me.AuthorFlairText = f.string()
+		case 7:
+			me.AuthorFlairCSS = f.string()
+		case 8:
+			me.PermaLink = f.string()
+		case 9:
+			me.CreatedUTC = f.double()
+		case 10:
+			me.Gilded = f.int()
+		case 11:
+			me.Score = f.int()
+		case 12:
+			me.UpVotes = f.int()
+		case 13:
+			me.DownVotes = f.int()
+		case 14:
+			me.Body = f.string()
+		case 15:
+			me.BodyHTML = f.string()
+		case 16:
+			me.EditedOn = redditTime(float64(int64(f.varint)))
+		case 17:
+			me.BannedOn = redditTime(float64(int64(f.varint)))
+		case 18:
+			me.AuthorStatus = f.string()
+		}
+
+		return nil
+	})
+
+	me.CreatedOn = redditTime(me.CreatedUTC)
+
+	return err
+}
+
+// MarshalProto encode the thread as a Thread message of proto/rscraper.proto
+func (me *Thread) MarshalProto() []byte {
+
+	var w protoWriter
+
+	w.message(1, me.Post.MarshalProto())
+
+	if me.Comments != nil {
+		for _, node := range me.Comments.Roots {
+			w.message(2, node.marshalProto())
+		}
+	}
+
+	for _, more := range me.More {
+		w.string(3, more)
+	}
+
+	w.int(4, protoTime(me.FetchedAt))
+
+	return w.buf
+}
+
+// UnmarshalProto decode a Thread message of proto/rscraper.proto into the thread
+func (me *Thread) UnmarshalProto(data []byte) error {
+
+	*me = Thread{Comments: &CommentTree{Roots: make([]*CommentNode, 0)}, More: make([]string, 0)}
+
+	return readProto(data, func(f *protoField) error {
+
+		switch f.number {
+		case 1:
+			return me.Post.UnmarshalProto(f.bytes)
+		case 2:
+
+			node := &CommentNode{}
+
+			if err := node.unmarshalProto(f.bytes); err != nil {
+				return err
+			}
+
+			me.Comments.Roots = append(me.Comments.Roots, node)
+		case 3:
+			me.More = append(me.More, f.string())
+		case 4:
+			me.FetchedAt = time.Unix(int64(f.varint), 0).UTC()
+		}
+
+		return nil
+	})
+}
+
+func (me *CommentNode) marshalProto() []byte {
+
+	var w protoWriter
+
+	w.message(1, me.Comment.MarshalProto())
+
+	for _, reply := range me.Replies {
+		w.message(2, reply.marshalProto())
+	}
+
+	return w.buf
+}
+
+func (me *CommentNode) unmarshalProto(data []byte) error {
+
+	me.Replies = make([]*CommentNode, 0)
+
+	return readProto(data, func(f *protoField) error {
+
+		switch f.number {
+		case 1:
+			return me.Comment.UnmarshalProto(f.bytes)
+		case 2:
+
+			reply := &CommentNode{}
+
+			if err := reply.unmarshalProto(f.bytes); err != nil {
+				return err
+			}
+
+			me.Replies = append(me.Replies, reply)
+		}
+
+		return nil
+	})
+}
+
+// ProtoSink writes Posts, Comments and Threads as length-delimited protocol buffer messages, each prefixed with its size as a varint, the framing used by writeDelimitedTo and parseDelimitedFrom in the protobuf libraries. A stream holds a single message type
+type ProtoSink struct {
+	mutex  sync.Mutex
+	writer *bufio.Writer
+}
+
+// NewProtoSink create a new sink writing length-delimited protocol buffer messages to w
+func NewProtoSink(w io.Writer) *ProtoSink {
+
+	return &ProtoSink{writer: bufio.NewWriter(w)}
+}
+
+// Write encode each Post, Comment or Thread item as a message
+func (me *ProtoSink) Write(items ...interface{}) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, item := range items {
+
+		var message []byte
+
+		switch value := item.(type) {
+		case Post:
+			message = value.MarshalProto()
+		case *Post:
+			message = value.MarshalProto()
+		case Comment:
+			message = value.MarshalProto()
+		case *Comment:
+			message = value.MarshalProto()
+		case Thread:
+			message = value.MarshalProto()
+		case *Thread:
+			message = value.MarshalProto()
+		default:
+			return fmt.Errorf("Cannot encode item of type %T as a protocol buffer", item)
+		}
+
+		if _, err := me.writer.Write(appendProtoVarint(nil, uint64(len(message)))); err != nil {
+			return err
+		}
+
+		if _, err := me.writer.Write(message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush write any buffered output to the underlying writer
+func (me *ProtoSink) Flush() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.writer.Flush()
+}
@@ -0,0 +1,64 @@
+package rscraper
+
+import (
+	"net/url"
+	"time"
+)
+
+// Provenance records how and when a scraped item was obtained. Page counts from zero, and is -1 when the position of the page in its listing is not known. For items read from a page cache, FetchedAt is when the page was originally fetched
+type Provenance struct {
+	Endpoint  string            `json:"endpoint"`
+	Query     map[string]string `json:"query,omitempty"`
+	FetchedAt time.Time         `json:"fetched_at"`
+	Page      int               `json:"page"`
+	UserAgent string            `json:"user_agent"`
+	Cached    bool              `json:"cached,omitempty"`
+}
+
+// newProvenance the provenance of items retrieved from a URL on the provided page of a listing, counting from zero
+func newProvenance(rawURL string, page int, cached bool) *Provenance {
+
+	result := &Provenance{FetchedAt: now().UTC(), Page: page, UserAgent: apiUserAgent, Cached: cached}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		result.Endpoint = parsed.Path
+		query := parsed.Query()
+
+		redactValues(query)
+
+		result.Query = flattenQuery(query)
+	}
+
+	return result
+}
+
+// copy a copy of the provenance, so items retrieved together can be annotated independently
+func (me *Provenance) copy() *Provenance {
+
+	result := *me
+
+	if me.Query != nil {
+
+		result.Query = make(map[string]string, len(me.Query))
+
+		for key, value := range me.Query {
+			result.Query[key] = value
+		}
+	}
+
+	return &result
+}
+
+func setPostProvenance(posts []Post, provenance *Provenance) {
+
+	for i := range posts {
+		posts[i].Provenance = provenance.copy()
+	}
+}
+
+func setCommentProvenance(comments []Comment, provenance *Provenance) {
+
+	for i := range comments {
+		comments[i].Provenance = provenance.copy()
+	}
+}
@@ -0,0 +1,104 @@
+package rscraper
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Query selects items from a store. Empty fields match every item
+type Query struct {
+	Subreddit string
+	Author    string
+	LinkFlair string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// QueryableStore a store that can answer queries itself rather than having every snapshot scanned, such as SQLStore
+type QueryableStore interface {
+	Store
+	Query(q Query) ([]Snapshot, error)
+}
+
+// QueryStore find the latest snapshot of every item in a store matching a query, newest item first
+func QueryStore(store Store, q Query) ([]Snapshot, error) {
+
+	if queryable, ok := store.(QueryableStore); ok {
+		return queryable.Query(q)
+	}
+
+	latest := make(map[string]Snapshot)
+
+	err := store.Snapshots(func(snapshot Snapshot) error {
+
+		if current, ok := latest[snapshot.Fullname]; !ok || snapshot.SavedAt.After(current.SavedAt) {
+			latest[snapshot.Fullname] = snapshot
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Snapshot, 0)
+
+	for _, snapshot := range latest {
+		if q.Matches(&snapshot) {
+			results = append(results, snapshot)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedOn().After(results[j].CreatedOn())
+	})
+
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
+	return results, nil
+}
+
+// Matches whether a snapshot satisfies the query. Link flair only matches posts
+func (me *Query) Matches(snapshot *Snapshot) bool {
+
+	var subreddit, author string
+
+	flair := ""
+
+	if snapshot.Post != nil {
+		subreddit, author, flair = snapshot.Post.Subreddit, snapshot.Post.Author, snapshot.Post.LinkFlairText
+	} else if snapshot.Comment != nil {
+		subreddit, author = snapshot.Comment.Subreddit, snapshot.Comment.Author
+	} else {
+		return false
+	}
+
+	if me.Subreddit != "" && !strings.EqualFold(me.Subreddit, subreddit) {
+		return false
+	}
+
+	if me.Author != "" && !strings.EqualFold(me.Author, author) {
+		return false
+	}
+
+	if me.LinkFlair != "" && (snapshot.Post == nil || me.LinkFlair != flair) {
+		return false
+	}
+
+	created := snapshot.CreatedOn()
+
+	if !me.Since.IsZero() && created.Before(me.Since) {
+		return false
+	}
+
+	if !me.Until.IsZero() && !created.Before(me.Until) {
+		return false
+	}
+
+	return true
+}
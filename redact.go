@@ -0,0 +1,188 @@
+package rscraper
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactedPlaceholder what redacted values are replaced with
+const redactedPlaceholder = "REDACTED"
+
+var (
+	redactMutex   sync.RWMutex
+	redactParams  = []string{"access_token", "refresh_token", "token", "password", "passwd", "client_secret", "secret", "api_key", "apikey"}
+	redactSecrets = make(map[string]bool)
+	redactHook    func(u *url.URL)
+
+	redactURLRegex = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+)
+
+// SetRedactedParams set the names of URL query and form parameters whose values are redacted from errors, dumps and logs. Names are compared case insensitively. By default access_token, refresh_token, token, password, passwd, client_secret, secret, api_key and apikey are redacted
+func SetRedactedParams(names ...string) {
+
+	redactMutex.Lock()
+	defer redactMutex.Unlock()
+
+	redactParams = append([]string(nil), names...)
+}
+
+// SetURLRedactor set a hook scrubbing URLs further, after user info passwords and redacted parameters have been removed. Passing nil removes the hook
+func SetURLRedactor(fn func(u *url.URL)) {
+
+	redactMutex.Lock()
+	defer redactMutex.Unlock()
+
+	redactHook = fn
+}
+
+// RedactSecret redact a secret value, such as a token or password, wherever it appears in errors, dumps and logs. Access tokens set with SetAccessToken and credentials passed to GetAccessToken are redacted automatically
+func RedactSecret(secret string) {
+
+	if secret == "" {
+		return
+	}
+
+	redactMutex.Lock()
+	defer redactMutex.Unlock()
+
+	redactSecrets[secret] = true
+}
+
+// Redact scrub text of registered secrets and of credentials in any URLs it contains
+func Redact(text string) string {
+
+	text = redactURLRegex.ReplaceAllStringFunc(text, RedactURL)
+
+	redactMutex.RLock()
+	defer redactMutex.RUnlock()
+
+	for secret := range redactSecrets {
+		text = strings.Replace(text, secret, redactedPlaceholder, -1)
+	}
+
+	return text
+}
+
+// RedactURL scrub a URL of its user info password and redacted query parameters, then apply the URL redactor hook. Text that does not parse as a URL is returned unchanged
+func RedactURL(rawURL string) string {
+
+	parsed, err := url.Parse(rawURL)
+
+	if err != nil {
+		return rawURL
+	}
+
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), redactedPlaceholder)
+	}
+
+	if parsed.RawQuery != "" {
+
+		query := parsed.Query()
+
+		if redactValues(query) {
+			parsed.RawQuery = query.Encode()
+		}
+	}
+
+	redactMutex.RLock()
+	hook := redactHook
+	redactMutex.RUnlock()
+
+	if hook != nil {
+		hook(parsed)
+	}
+
+	return parsed.String()
+}
+
+// redactValues replace the values of redacted parameters, reporting whether any were replaced
+func redactValues(values url.Values) bool {
+
+	redactMutex.RLock()
+	defer redactMutex.RUnlock()
+
+	replaced := false
+
+	for name, list := range values {
+		for _, param := range redactParams {
+
+			if !strings.EqualFold(name, param) {
+				continue
+			}
+
+			for i := range list {
+				list[i] = redactedPlaceholder
+			}
+
+			replaced = true
+		}
+	}
+
+	return replaced
+}
+
+// redactJSON replace the string values of redacted parameters wherever they appear as keys in a JSON document, such as the access_token of a token response, along with any registered secrets
+func redactJSON(data []byte) []byte {
+
+	redactMutex.RLock()
+	defer redactMutex.RUnlock()
+
+	if len(redactParams) > 0 {
+
+		names := make([]string, len(redactParams))
+
+		for i, name := range redactParams {
+			names[i] = regexp.QuoteMeta(name)
+		}
+
+		keys := regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+		data = keys.ReplaceAll(data, []byte(`${1}"`+redactedPlaceholder+`"`))
+	}
+
+	for secret := range redactSecrets {
+		data = bytes.Replace(data, []byte(secret), []byte(redactedPlaceholder), -1)
+	}
+
+	return data
+}
+
+// redactError an error whose message has been scrubbed by Redact. Errors with nothing to redact are returned as is, and URL errors keep their type
+func redactError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		return &url.Error{Op: urlErr.Op, URL: RedactURL(urlErr.URL), Err: redactError(urlErr.Err)}
+	}
+
+	message := err.Error()
+
+	if redacted := Redact(message); redacted != message {
+		return &redactedError{message: redacted, err: err}
+	}
+
+	return err
+}
+
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (me *redactedError) Error() string {
+
+	return me.message
+}
+
+// Unwrap the original error, so errors.Is and errors.As still see through redaction
+func (me *redactedError) Unwrap() error {
+
+	return me.err
+}
@@ -0,0 +1,122 @@
+package rscraper
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// renderTimeLayout the layout of timestamps in rendered threads
+const renderTimeLayout = "2006-01-02 15:04 MST"
+
+var threadHTMLTemplate = template.Must(template.New("thread").Funcs(template.FuncMap{
+	"time":      renderTime,
+	"permalink": func(permalink string) string { return apiPermalinkHost + permalink },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Post.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; color: #222; }
+.meta { color: #777; font-size: 0.85em; }
+.body { white-space: pre-wrap; margin: 0.4em 0 0.8em 0; }
+.comment { border-left: 2px solid #ddd; padding-left: 0.8em; margin-left: 0.4em; }
+</style>
+</head>
+<body>
+{{if .Index}}<p class="meta"><a href="{{.Index}}">&larr; Index</a></p>{{end}}
+<h1><a href="{{permalink .Post.PermaLink}}">{{.Post.Title}}</a></h1>
+<div class="meta">{{.Post.Author}} &middot; {{.Post.Score}} points &middot; {{time .Post.CreatedOn}}{{if not .Post.EditedOn.IsZero}} &middot; edited {{time .Post.EditedOn}}{{end}}{{if .Post.LinkFlairText}} &middot; {{.Post.LinkFlairText}}{{end}}</div>
+{{if .Post.IsSelf}}<div class="body">{{.Post.Text}}</div>{{else}}<p><a href="{{.Post.URL}}">{{.Post.URL}}</a></p>{{end}}
+{{if .Comments}}{{template "comments" .Comments.Roots}}{{end}}
+</body>
+</html>
+{{define "comments"}}{{range .}}<div class="comment">
+<div class="meta">{{.Comment.Author}} &middot; {{.Comment.Score}} points &middot; {{time .Comment.CreatedOn}}{{if not .Comment.EditedOn.IsZero}} &middot; edited {{time .Comment.EditedOn}}{{end}}</div>
+<div class="body">{{.Comment.Body}}</div>
+{{template "comments" .Replies}}</div>
+{{end}}{{end}}`))
+
+// RenderMarkdown write a thread as Markdown, with each level of replies nested one blockquote deeper
+func RenderMarkdown(w io.Writer, thread *Thread) error {
+
+	writer := bufio.NewWriter(w)
+
+	post := thread.Post
+
+	fmt.Fprintf(writer, "# [%s](%s%s)\n\n", post.Title, apiPermalinkHost, post.PermaLink)
+	fmt.Fprintf(writer, "*%s · %d points · %s", post.Author, post.Score, renderTime(post.CreatedOn))
+
+	if !post.EditedOn.IsZero() {
+		fmt.Fprintf(writer, " · edited %s", renderTime(post.EditedOn))
+	}
+
+	if post.LinkFlairText != "" {
+		fmt.Fprintf(writer, " · %s", post.LinkFlairText)
+	}
+
+	writer.WriteString("*\n\n")
+
+	if post.IsSelf {
+		if post.Text != "" {
+			writer.WriteString(post.Text + "\n\n")
+		}
+	} else {
+		fmt.Fprintf(writer, "<%s>\n\n", post.URL)
+	}
+
+	if thread.Comments != nil {
+		thread.Comments.Walk(func(node *CommentNode, depth int) {
+
+			prefix := strings.Repeat(">", depth+1) + " "
+
+			comment := node.Comment
+
+			header := fmt.Sprintf("**%s** · %d points · %s", comment.Author, comment.Score, renderTime(comment.CreatedOn))
+
+			if !comment.EditedOn.IsZero() {
+				header += " · edited " + renderTime(comment.EditedOn)
+			}
+
+			writer.WriteString(prefix + header + "\n" + strings.TrimSpace(prefix) + "\n")
+
+			for _, line := range strings.Split(comment.Body, "\n") {
+				writer.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+			}
+
+			writer.WriteString("\n")
+		})
+	}
+
+	return writer.Flush()
+}
+
+// RenderHTML write a thread as a standalone HTML page, with each level of replies indented one step further
+func RenderHTML(w io.Writer, thread *Thread) error {
+
+	return renderHTMLPage(w, thread, "")
+}
+
+// threadHTMLPage a thread rendered as HTML, with a link back to the index of the site it belongs to when Index is set
+type threadHTMLPage struct {
+	*Thread
+	Index string
+}
+
+func renderHTMLPage(w io.Writer, thread *Thread, index string) error {
+
+	return threadHTMLTemplate.Execute(w, threadHTMLPage{Thread: thread, Index: index})
+}
+
+func renderTime(t time.Time) string {
+
+	if t.IsZero() {
+		return "unknown time"
+	}
+
+	return t.Format(renderTimeLayout)
+}
@@ -0,0 +1,124 @@
+package rscraper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Repost a submission whose canonical URL was already submitted recently
+type Repost struct {
+	Post     Post
+	URL      string
+	Original string
+}
+
+// RepostMonitor watches the new listing of a set of subreddits and flags link submissions whose URL was already posted
+type RepostMonitor struct {
+	Subreddits []string
+	Interval   time.Duration
+	Store      DedupeStore
+}
+
+// NewRepostMonitor create a new repost monitor for the provided subreddits, remembering URLs in store
+func NewRepostMonitor(store DedupeStore, interval time.Duration, subreddits ...string) *RepostMonitor {
+
+	return &RepostMonitor{Subreddits: subreddits, Interval: interval, Store: store}
+}
+
+// Watch poll the subreddits until the context is cancelled, emitting each repost found. Store errors are delivered on the error channel alongside listing errors
+func (me *RepostMonitor) Watch(ctx context.Context) (<-chan Repost, <-chan error) {
+
+	reposts := make(chan Repost)
+	errs := make(chan error, 1)
+
+	posts, streamErrs := StreamPosts(ctx, me.Interval, me.Subreddits...)
+
+	go func() {
+
+		defer close(reposts)
+		defer close(errs)
+
+		for {
+			select {
+			case err, ok := <-streamErrs:
+
+				if !ok {
+					streamErrs = nil
+					continue
+				}
+
+				sendError(errs, err)
+			case post, ok := <-posts:
+
+				if !ok {
+					return
+				}
+
+				if post.IsSelf {
+					continue
+				}
+
+				canonical := CanonicalURL(post.URL)
+
+				original, duplicate, err := me.Store.Add(canonical, post.Fullname())
+
+				if err != nil {
+					sendError(errs, err)
+					continue
+				}
+
+				if !duplicate || original == post.Fullname() {
+					continue
+				}
+
+				select {
+				case reposts <- Repost{Post: post, URL: canonical, Original: original}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return reposts, errs
+}
+
+// CanonicalURL normalize a submitted URL so that trivially different links to the same content compare equal. The scheme, "www." and "m." host prefixes, fragments, trailing slashes and tracking parameters are removed, and youtu.be links are expanded
+func CanonicalURL(link string) string {
+
+	parsed, err := url.Parse(strings.TrimSpace(link))
+
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+
+	host := strings.ToLower(parsed.Host)
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	query := parsed.Query()
+
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") || key == "ref" || key == "fbclid" || key == "gclid" {
+			query.Del(key)
+		}
+	}
+
+	path := strings.TrimRight(parsed.Path, "/")
+
+	if host == "youtu.be" && path != "" {
+		query.Set("v", path[1:])
+		host = "youtube.com"
+		path = "/watch"
+	}
+
+	result := host + path
+
+	if encoded := query.Encode(); encoded != "" {
+		result += "?" + encoded
+	}
+
+	return result
+}
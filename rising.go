@@ -0,0 +1,193 @@
+package rscraper
+
+import (
+	"context"
+	"time"
+)
+
+// risingDefaultMaxAge how long a rising detector tracks a candidate post by default
+const risingDefaultMaxAge = 6 * time.Hour
+
+// RisingDetector watches a subreddit's new and rising listings and emits posts the moment they cross score and comment thresholds. Every threshold left at zero is ignored; a post is emitted once all the others are met
+type RisingDetector struct {
+	Subreddit string
+	Interval  time.Duration
+
+	MinScore    int
+	MinComments int
+
+	// MinScorePerMinute and MinCommentsPerMinute velocity thresholds, measured between the two most recent checks of a post, or since the post was created on its first check
+	MinScorePerMinute    float64
+	MinCommentsPerMinute float64
+
+	// MaxAge stop tracking posts older than this without emitting them. Defaults to six hours
+	MaxAge time.Duration
+}
+
+type risingCandidate struct {
+	post      Post
+	checkedAt time.Time
+}
+
+// NewRisingDetector create a new detector polling a subreddit at the provided interval
+func NewRisingDetector(subreddit string, interval time.Duration) *RisingDetector {
+
+	return &RisingDetector{Subreddit: subreddit, Interval: interval, MaxAge: risingDefaultMaxAge}
+}
+
+// Run poll the new and rising listings and re-check candidate posts until the context is cancelled, emitting each post once when it crosses the thresholds. Errors do not stop the detector; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise
+func (me *RisingDetector) Run(ctx context.Context) (<-chan Post, <-chan error) {
+
+	posts := make(chan Post)
+	errs := make(chan error, 1)
+
+	interval := politeInterval(me.Interval, me.Subreddit)
+
+	go func() {
+
+		defer close(posts)
+		defer close(errs)
+
+		candidates := make(map[string]*risingCandidate)
+		emitted := newSeenSet(streamSeenLimit)
+
+		for {
+			for _, post := range me.poll(candidates, emitted, errs) {
+				select {
+				case posts <- post:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return posts, errs
+}
+
+// poll add new candidates from the listings, re-check every candidate and return those that crossed the thresholds
+func (me *RisingDetector) poll(candidates map[string]*risingCandidate, emitted *seenSet, errs chan<- error) []Post {
+
+	for _, listingType := range []string{ListingTypeNew, ListingTypeRising} {
+
+		page, _, err := GetPosts(me.Subreddit, listingType, "", "")
+
+		if err != nil {
+			sendError(errs, err)
+			continue
+		}
+
+		for _, post := range page {
+
+			fullname := post.Fullname()
+
+			if _, ok := candidates[fullname]; !ok && !emitted.ids[fullname] {
+				candidates[fullname] = nil
+			}
+		}
+	}
+
+	fullnames := make([]string, 0, len(candidates))
+
+	for fullname := range candidates {
+		fullnames = append(fullnames, fullname)
+	}
+
+	risen := make([]Post, 0)
+
+	maxAge := me.MaxAge
+
+	if maxAge <= 0 {
+		maxAge = risingDefaultMaxAge
+	}
+
+	for start := 0; start < len(fullnames); start += apiMaxIDsPerRequest {
+
+		end := start + apiMaxIDsPerRequest
+
+		if end > len(fullnames) {
+			end = len(fullnames)
+		}
+
+		posts, err := getPostsByID(fullnames[start:end])
+
+		if err != nil {
+			sendError(errs, err)
+			continue
+		}
+
+		checkedAt := now()
+
+		returned := make(map[string]bool, len(posts))
+
+		for _, post := range posts {
+
+			fullname := post.Fullname()
+
+			returned[fullname] = true
+
+			previous := candidates[fullname]
+
+			switch {
+			case me.crossed(&post, previous, checkedAt):
+				delete(candidates, fullname)
+				emitted.add(fullname)
+				risen = append(risen, post)
+			case checkedAt.Sub(createdInstant(post.CreatedUTC)) > maxAge:
+				delete(candidates, fullname)
+			default:
+				candidates[fullname] = &risingCandidate{post: post, checkedAt: checkedAt}
+			}
+		}
+
+		for _, fullname := range fullnames[start:end] {
+			if !returned[fullname] {
+				delete(candidates, fullname)
+			}
+		}
+	}
+
+	return risen
+}
+
+// crossed whether a post meets every threshold that is set
+func (me *RisingDetector) crossed(post *Post, previous *risingCandidate, checkedAt time.Time) bool {
+
+	if post.Score < me.MinScore || post.NumComments < me.MinComments {
+		return false
+	}
+
+	if me.MinScorePerMinute <= 0 && me.MinCommentsPerMinute <= 0 {
+		return true
+	}
+
+	since := createdInstant(post.CreatedUTC)
+	score, comments := 0, 0
+
+	if previous != nil {
+		since = previous.checkedAt
+		score, comments = previous.post.Score, previous.post.NumComments
+	}
+
+	minutes := checkedAt.Sub(since).Minutes()
+
+	if minutes <= 0 {
+		return false
+	}
+
+	if me.MinScorePerMinute > 0 && float64(post.Score-score)/minutes < me.MinScorePerMinute {
+		return false
+	}
+
+	if me.MinCommentsPerMinute > 0 && float64(post.NumComments-comments)/minutes < me.MinCommentsPerMinute {
+		return false
+	}
+
+	return true
+}
@@ -1,6 +1,7 @@
 package rscraper
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,15 +9,20 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 )
 
 const (
-	apiUserAgent             = "rscrape_golang_tool/v0.1-alpha"
+	// Version the version of this library
+	Version = "0.1-alpha"
+
+	apiUserAgent             = "rscrape_golang_tool/v" + Version
 	apiIDRegex               = "^t(1|3|5)_[A-Za-z0-9]{5,9}$"
 	apiObjectTypeListing     = "Listing"
 	apiObjectTypeComment     = "t1"
 	apiObjectTypePost        = "t3"
+	apiObjectTypeAccount     = "t2"
 	apiObjectTypeSubreddit   = "t5"
 	apiObjectTypeMoreReplies = "more"
 
@@ -29,6 +35,9 @@ const (
 	// ListingTypeTop get top posts in a subreddit
 	ListingTypeTop = "top"
 
+	// ListingTypeRising get posts gaining votes quickly in a subreddit
+	ListingTypeRising = "rising"
+
 	// ListingTopAllTime get top posts of all time in a subreddit
 	ListingTopAllTime = "all"
 
@@ -64,34 +73,89 @@ type moreReplies struct {
 
 // Subreddit a subreddit on reddit
 type Subreddit struct {
-	ID         string  `json:"id"`
-	Name       string  `json:"display_name"`
-	URL        string  `json:"url"`
-	Title      string  `json:"title"`
-	CreatedUTC float64 `json:"created_utc"`
-	CreatedOn  time.Time
+	ID                    string  `json:"id"`
+	Name                  string  `json:"display_name"`
+	URL                   string  `json:"url"`
+	Title                 string  `json:"title"`
+	Description           string  `json:"public_description"`
+	Sidebar               string  `json:"description"`
+	Subscribers           int     `json:"subscribers"`
+	ActiveUsers           int     `json:"active_user_count"`
+	IconImg               string  `json:"icon_img"`
+	CommunityIcon         string  `json:"community_icon"`
+	HeaderImg             string  `json:"header_img"`
+	BannerImg             string  `json:"banner_img"`
+	BannerBackgroundImage string  `json:"banner_background_image"`
+	SubredditType         string  `json:"subreddit_type"`
+	Over18                bool    `json:"over18"`
+	CreatedUTC            float64 `json:"created_utc"`
+	CreatedOn             time.Time
 }
 
 // Post a post on a subreddit
 type Post struct {
-	ID              string  `json:"id"`
-	SubredditID     string  `json:"subreddit_id"`
-	Author          string  `json:"author"`
-	LinkFlairText   string  `json:"link_flair_text"`
-	LinkFlairCSS    string  `json:"link_flair_css_class"`
-	AuthorFlairText string  `json:"author_flair_text"`
-	AuthorFlairCSS  string  `json:"author_flair_css_class"`
-	Title           string  `json:"title"`
-	URL             string  `json:"url"`
-	PermaLink       string  `json:"permalink"`
-	CreatedUTC      float64 `json:"created_utc"`
-	Gilded          int     `json:"gilded"`
-	Score           int     `json:"score"`
-	UpVotes         int     `json:"ups"`
-	DownVotes       int     `json:"downs"`
-	Text            string  `json:"selftext"`
-	TextHTML        string  `json:"selftext_html"`
-	CreatedOn       time.Time
+	ID                  string   `json:"id"`
+	SubredditID         string   `json:"subreddit_id"`
+	Subreddit           string   `json:"subreddit"`
+	SubredditType       string   `json:"subreddit_type"`
+	Author              string   `json:"author"`
+	LinkFlairText       string   `json:"link_flair_text"`
+	LinkFlairCSS        string   `json:"link_flair_css_class"`
+	LinkFlairTemplateID string   `json:"link_flair_template_id"`
+	AuthorFlairText     string   `json:"author_flair_text"`
+	AuthorFlairCSS      string   `json:"author_flair_css_class"`
+	Title               string   `json:"title"`
+	URL                 string   `json:"url"`
+	PermaLink           string   `json:"permalink"`
+	CreatedUTC          float64  `json:"created_utc"`
+	Gilded              int      `json:"gilded"`
+	Score               int      `json:"score"`
+	UpvoteRatio         float64  `json:"upvote_ratio"`
+	UpVotes             int      `json:"ups"`
+	DownVotes           int      `json:"downs"`
+	NumComments         int      `json:"num_comments"`
+	Text                string   `json:"selftext"`
+	TextHTML            string   `json:"selftext_html"`
+	Thumbnail           string   `json:"thumbnail"`
+	Preview             *Preview `json:"preview"`
+	IsSelf              bool     `json:"is_self"`
+	Over18              bool     `json:"over_18"`
+	Locked              bool     `json:"locked"`
+	Archived            bool     `json:"archived"`
+	TotalAwards         int      `json:"total_awards_received"`
+	AllAwardings        []Award  `json:"all_awardings"`
+	CreatedOn           time.Time
+	EditedOn            time.Time
+	BannedOn            time.Time
+	Provenance          *Provenance `json:"provenance,omitempty"`
+	AuthorAccount       *User       `json:"author_account,omitempty"`
+	AuthorStatus        string      `json:"author_status,omitempty"`
+}
+
+// Preview preview images generated by reddit for a post
+type Preview struct {
+	Images []PreviewImage `json:"images"`
+}
+
+// PreviewImage a preview image at its source resolution and downscaled resolutions, smallest first
+type PreviewImage struct {
+	Source      ImageSource   `json:"source"`
+	Resolutions []ImageSource `json:"resolutions"`
+}
+
+// ImageSource the URL and dimensions of one resolution of an image
+type ImageSource struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Award an award given to a post or comment
+type Award struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+	CoinPrice int    `json:"coin_price"`
 }
 
 // Comment a comment on a post
@@ -99,6 +163,8 @@ type Comment struct {
 	ID              string          `json:"id"`
 	PostID          string          `json:"link_id"`
 	ParentID        string          `json:"parent_id"`
+	Subreddit       string          `json:"subreddit"`
+	SubredditType   string          `json:"subreddit_type"`
 	Author          string          `json:"author"`
 	AuthorFlairText string          `json:"author_flair_text"`
 	AuthorFlairCSS  string          `json:"author_flair_css_class"`
@@ -110,9 +176,15 @@ type Comment struct {
 	DownVotes       int             `json:"downs"`
 	Body            string          `json:"body"`
 	BodyHTML        string          `json:"body_html"`
+	Over18          bool            `json:"over_18"`
 	Replies         json.RawMessage `json:"replies"`
 	RepliesAfter    []string
 	CreatedOn       time.Time
+	EditedOn        time.Time
+	BannedOn        time.Time
+	Provenance      *Provenance `json:"provenance,omitempty"`
+	AuthorAccount   *User       `json:"author_account,omitempty"`
+	AuthorStatus    string      `json:"author_status,omitempty"`
 }
 
 func (me *Comment) extractReplies() ([]Comment, error) {
@@ -127,7 +199,7 @@ func (me *Comment) extractReplies() ([]Comment, error) {
 
 	var repliesObject apiObject
 
-	err := json.Unmarshal(me.Replies, &repliesObject)
+	err := unmarshal(me.Replies, &repliesObject)
 
 	if err != nil {
 		return replies, err
@@ -182,130 +254,182 @@ func GetSubreddit(subreddit string) (*Subreddit, error) {
 
 	object, err := getResponse(redditURL.String())
 
+	if err != nil {
+		return nil, subredditError(subreddit, err)
+	}
+
+	if object.Type == apiObjectTypeListing {
+		return nil, &SubredditStatusError{Subreddit: subreddit, Status: SubredditStatusNotFound}
+	}
+
+	result, err := extractSubreddit(object)
+
 	if err != nil {
 		return nil, err
 	}
 
-	return extractSubreddit(object)
+	return result, guardrailsCheckSubreddit(result)
 }
 
-// GetPosts retrieves all posts from the specified
+// GetPosts retrieves all posts from the specified. The cursor does not reveal how far into the listing a page is, so pages after the first are recorded with an unknown page index; use GetPostsPage when paging through a listing
 func GetPosts(subreddit, listingType, after, topType string) ([]Post, string, error) {
 
-	posts := make([]Post, 0)
+	page := 0
+
+	if after != "" {
+		page = -1
+	}
+
+	return GetPostsPage(subreddit, listingType, after, topType, page)
+}
+
+// GetPostsPage retrieve a page of posts, recording its index in the listing, counting from zero, in the provenance of each post
+func GetPostsPage(subreddit, listingType, after, topType string, page int) ([]Post, string, error) {
 
 	redditURL := getPostsURL(subreddit, listingType, after, topType)
 
-	object, err := getResponse(redditURL.String())
+	posts, after, err := getPostListingPage(redditURL.String(), page)
 
 	if err != nil {
-		return nil, "", err
+		return nil, "", subredditError(subreddit, err)
 	}
 
-	list, err := extractListing(object)
+	return posts, after, nil
+}
+
+// GetComments retrieves comments for a particular post
+func GetComments(subreddit, postID, after string) ([]Comment, []string, error) {
+
+	_, comments, more, err := getThread(context.Background(), getCommentsURL(subreddit, postID, after))
+
+	return comments, more, err
+}
+
+// GetPostWithComments retrieves a post along with its comments, in a single request
+func GetPostWithComments(subreddit, postID, after string) (*Post, []Comment, []string, error) {
+
+	return getThread(context.Background(), getCommentsURL(subreddit, postID, after))
+}
+
+// getThread retrieve a post and its comments from the comments endpoint
+func getThread(ctx context.Context, redditURL *url.URL) (*Post, []Comment, []string, error) {
+
+	objects, err := getResponsesContext(ctx, redditURL.String())
 
 	if err != nil {
-		return nil, "", err
+		return nil, nil, nil, err
 	}
 
-	after = ""
+	post, comments, more, err := extractThread(objects)
 
-	if ok, _ := regexp.MatchString(apiIDRegex, list.After); ok {
-		after = list.After
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	for _, child := range list.Children {
+	provenance := newProvenance(redditURL.String(), 0, false)
 
-		post, err := extractPost(&child)
+	post.Provenance = provenance.copy()
 
-		if err != nil {
-			return nil, "", err
-		}
+	setCommentProvenance(comments, provenance)
 
-		posts = append(posts, *post)
-	}
+	return post, comments, more, nil
+}
 
-	return posts, after, nil
+func getPostsByID(fullnames []string) ([]Post, error) {
+
+	redditURL := getPostsByIDURL(fullnames)
+
+	posts, _, err := getPostListing(redditURL.String())
+
+	return posts, err
 }
 
-// GetComments retrieves comments for a particular post
-func GetComments(subreddit, postID, after string) ([]Comment, []string, error) {
+func getPostListing(url string) ([]Post, string, error) {
 
-	comments := make([]Comment, 0)
+	return getPostListingPage(url, 0)
+}
 
-	redditURL := getCommentsURL(subreddit, postID, after)
+func getPostListingPage(url string, page int) ([]Post, string, error) {
 
-	objects, err := getResponses(redditURL.String())
+	bytes, err := get(url)
 
 	if err != nil {
+		return nil, "", err
+	}
+
+	posts, after, err := parsePostListing(bytes)
+
+	setPostProvenance(posts, newProvenance(url, page, false))
+
+	return posts, after, err
+}
+
+// DecodeListing decode a listing page of posts as returned by reddit, such as ListingFixture, along with the fullname of the post to request the next page after
+func DecodeListing(data []byte) ([]Post, string, error) {
+
+	return parsePostListing(data)
+}
+
+// DecodeThread decode a comments endpoint response as returned by reddit, such as ThreadFixture, into the post and its comments
+func DecodeThread(data []byte) (*Post, []Comment, error) {
+
+	objects := make([]apiObject, 0)
+
+	if err := unmarshal(data, &objects); err != nil {
 		return nil, nil, err
 	}
 
-	var list *listing
+	post, comments, _, err := extractThread(objects)
 
-	for _, object := range objects {
+	return post, comments, err
+}
 
-		list, err = extractListing(&object)
+func parsePostListing(bytes []byte) ([]Post, string, error) {
 
-		if err != nil {
-			list = nil
-			continue
-		}
+	posts, after, _, err := parsePostListingCount(bytes)
 
-		if list.Children == nil || len(list.Children) == 0 {
-			list = nil
-			continue
-		}
+	return posts, after, err
+}
 
-		_, err = extractComment(&(list.Children[0]))
+// parsePostListingCount parse a listing of posts, dropping the posts the guardrails refuse, along with the number of posts in the listing before any were dropped
+func parsePostListingCount(bytes []byte) ([]Post, string, int, error) {
 
-		if err == nil {
-			break
-		} else {
-			list = nil
-		}
+	posts := make([]Post, 0)
+
+	var object apiObject
+
+	if err := unmarshal(bytes, &object); err != nil {
+		return nil, "", 0, err
 	}
 
-	if list == nil {
-		return nil, nil, errors.New("No comment listings found")
+	list, err := extractListing(&object)
+
+	if err != nil {
+		return nil, "", 0, err
 	}
 
-	after = ""
+	after := ""
 
 	if ok, _ := regexp.MatchString(apiIDRegex, list.After); ok {
 		after = list.After
 	}
 
-	more := make([]string, 0)
-
 	for _, child := range list.Children {
 
-		comment, err := extractComment(&child)
+		post, err := extractPost(&child)
 
 		if err != nil {
-
-			moreComments, err := extractMore(&child)
-
-			if err != nil {
-				return nil, nil, errors.New("API Object is not a Comment or More Replies")
-			}
-
-			more = append(more, moreComments...)
-			continue
+			return nil, "", 0, err
 		}
 
-		comments = append(comments, *comment)
-
-		commentReplies, err := comment.extractReplies()
-
-		if err != nil {
-			return nil, nil, err
+		if !guardrailsAllowPost(post) {
+			continue
 		}
 
-		comments = append(comments, commentReplies...)
+		posts = append(posts, *post)
 	}
 
-	return comments, more, nil
+	return posts, after, len(list.Children), nil
 }
 
 func getResponse(url string) (*apiObject, error) {
@@ -318,29 +442,37 @@ func getResponse(url string) (*apiObject, error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(bytes, &object)
+	err = unmarshal(bytes, &object)
 
 	return &object, nil
 }
 
 func getResponses(url string) ([]apiObject, error) {
 
+	return getResponsesContext(context.Background(), url)
+}
+
+func getResponsesContext(ctx context.Context, url string) ([]apiObject, error) {
+
 	objects := make([]apiObject, 0)
 
-	bytes, err := get(url)
+	bytes, err := getContext(ctx, url)
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(bytes, &objects)
+	err = unmarshal(bytes, &objects)
 
 	return objects, err
 }
 
 func get(url string) ([]byte, error) {
 
-	client := &http.Client{}
+	return getContext(context.Background(), url)
+}
+
+func getContext(ctx context.Context, url string) ([]byte, error) {
 
 	req, err := http.NewRequest("GET", url, nil)
 
@@ -348,17 +480,104 @@ func get(url string) ([]byte, error) {
 		return nil, err
 	}
 
+	req = req.WithContext(ctx)
+
+	if token := accessToken(); token != "" {
+		req.Header.Set("Authorization", "bearer "+token)
+	}
+
+	return do(req)
+}
+
+// head retrieve the headers of a URL without its body
+func head(url string) (http.Header, error) {
+
+	req, err := http.NewRequest("HEAD", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := waitForRequestSlot(req.Context()); err != nil {
+		return nil, err
+	}
+
 	req.Header.Set("User-Agent", apiUserAgent)
 
-	resp, err := client.Do(req)
+	resp, err := (&http.Client{}).Do(req)
+
+	if err != nil {
+		return nil, redactError(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newResponseError(resp.StatusCode, nil)
+	}
+
+	return resp.Header, nil
+}
+
+func post(url string, form url.Values) ([]byte, error) {
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(form.Encode()))
 
 	if err != nil {
 		return nil, err
 	}
 
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if token := accessToken(); token != "" {
+		req.Header.Set("Authorization", "bearer "+token)
+	}
+
+	return do(req)
+}
+
+func do(req *http.Request) ([]byte, error) {
+
+	client := &http.Client{}
+
+	if err := waitForRequestSlot(req.Context()); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", apiUserAgent)
+
+	rawRequest := dumpWARCRequest(req)
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, redactError(err)
+	}
+
 	defer resp.Body.Close()
 
-	return ioutil.ReadAll(resp.Body)
+	bytes, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recordWARC(rawRequest, resp, bytes)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newResponseError(resp.StatusCode, bytes)
+	}
+
+	return bytes, nil
+}
+
+func getPostsByIDURL(fullnames []string) *url.URL {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/by_id/%s.json", strings.Join(fullnames, ","))
+
+	return redditURL
 }
 
 func getSubredditURL(subreddit string) *url.URL {
@@ -413,9 +632,7 @@ func getCommentsURL(subreddit, postID, after string) *url.URL {
 
 	redditURL := getBaseURL()
 
-	if postID[0:3] == "t3_" {
-		postID = postID[3:]
-	}
+	postID = strings.TrimPrefix(postID, "t3_")
 
 	redditURL.Path = fmt.Sprintf("/r/%s/comments/%s.json", subreddit, postID)
 
@@ -437,6 +654,10 @@ func getBaseURL() *url.URL {
 	redditURL.Scheme = "https"
 	redditURL.Host = "reddit.com"
 
+	if accessToken() != "" {
+		redditURL.Host = apiOAuthHost
+	}
+
 	return &redditURL
 }
 
@@ -448,7 +669,7 @@ func extractListing(object *apiObject) (*listing, error) {
 
 	var result listing
 
-	err := json.Unmarshal(object.Data, &result)
+	err := unmarshal(object.Data, &result)
 
 	return &result, err
 }
@@ -461,13 +682,13 @@ func extractSubreddit(object *apiObject) (*Subreddit, error) {
 
 	var result Subreddit
 
-	err := json.Unmarshal(object.Data, &result)
+	err := unmarshal(object.Data, &result)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result.CreatedOn = time.Unix(int64(result.CreatedUTC), 0)
+	result.CreatedOn = redditTime(result.CreatedUTC)
 	return &result, err
 }
 
@@ -479,13 +700,14 @@ func extractPost(object *apiObject) (*Post, error) {
 
 	var result Post
 
-	err := json.Unmarshal(object.Data, &result)
+	err := unmarshal(object.Data, &result)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result.CreatedOn = time.Unix(int64(result.CreatedUTC), 0)
+	result.CreatedOn = redditTime(result.CreatedUTC)
+	result.EditedOn, result.BannedOn = extractModifiedTimes(object)
 	return &result, err
 }
 
@@ -497,16 +719,100 @@ func extractComment(object *apiObject) (*Comment, error) {
 
 	var result Comment
 
-	err := json.Unmarshal(object.Data, &result)
+	err := unmarshal(object.Data, &result)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result.CreatedOn = time.Unix(int64(result.CreatedUTC), 0)
+	result.CreatedOn = redditTime(result.CreatedUTC)
+	result.EditedOn, result.BannedOn = extractModifiedTimes(object)
 	return &result, err
 }
 
+// extractThread parse the response of the comments endpoint by position: a listing holding the post, followed by a listing of its comments, which is empty for threads without comments
+func extractThread(objects []apiObject) (*Post, []Comment, []string, error) {
+
+	if len(objects) != 2 {
+		return nil, nil, nil, fmt.Errorf("Expected a post listing and a comment listing, found %d API Objects", len(objects))
+	}
+
+	postList, err := extractListing(&objects[0])
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(postList.Children) == 0 {
+		return nil, nil, nil, errors.New("Post listing is empty")
+	}
+
+	post, err := extractPost(&postList.Children[0])
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := guardrailsCheckPost(post); err != nil {
+		return nil, nil, nil, err
+	}
+
+	commentList, err := extractListing(&objects[1])
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	comments, more, err := extractComments(commentList)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return post, comments, more, nil
+}
+
+// extractComments flatten a listing of comments and their replies, returning the comments along with the IDs of further comments that were not included
+func extractComments(list *listing) ([]Comment, []string, error) {
+
+	comments := make([]Comment, 0)
+
+	more := make([]string, 0)
+
+	for _, child := range list.Children {
+
+		comment, err := extractComment(&child)
+
+		if err != nil {
+
+			moreComments, err := extractMore(&child)
+
+			if err != nil {
+				return nil, nil, errors.New("API Object is not a Comment or More Replies")
+			}
+
+			more = append(more, moreComments...)
+			continue
+		}
+
+		if !guardrailsAllowComment(comment) {
+			continue
+		}
+
+		comments = append(comments, *comment)
+
+		commentReplies, err := comment.extractReplies()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		comments = append(comments, commentReplies...)
+	}
+
+	return comments, more, nil
+}
+
 func extractMore(object *apiObject) ([]string, error) {
 
 	if object == nil || object.Type != apiObjectTypeMoreReplies {
@@ -515,7 +821,7 @@ func extractMore(object *apiObject) ([]string, error) {
 
 	var result moreReplies
 
-	err := json.Unmarshal(object.Data, &result)
+	err := unmarshal(object.Data, &result)
 
 	if err != nil {
 		return nil, err
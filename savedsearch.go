@@ -0,0 +1,268 @@
+package rscraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// SavedSearch a named search query and the posts it has already matched
+type SavedSearch struct {
+	Name string `json:"name"`
+
+	// Subreddit the subreddit to search, or empty to search all of reddit
+	Subreddit string `json:"subreddit"`
+
+	Query string `json:"query"`
+
+	// Seen the IDs of the most recent posts the search has matched, oldest first
+	Seen []string `json:"seen"`
+
+	// Primed whether the search has run at least once. Posts matched by the first run are recorded as seen without being routed to the sink. Later matches are only recorded as seen once the sink has written and flushed them, so a failed write is retried on the next run
+	Primed bool `json:"primed"`
+}
+
+// SearchSubscriptions re-runs saved searches at an interval and routes posts they have not matched before to a sink per search. Searches and the posts they have matched are persisted to a JSON file, so subscriptions resume where they left off
+type SearchSubscriptions struct {
+	Interval time.Duration
+	Path     string
+
+	mutex    sync.Mutex
+	searches map[string]*SavedSearch
+	seen     map[string]*seenSet
+	sinks    map[string]Sink
+}
+
+// LoadSearchSubscriptions load saved searches from a JSON file, which need not exist yet. Loaded searches are not run until they are subscribed to again with a sink
+func LoadSearchSubscriptions(path string, interval time.Duration) (*SearchSubscriptions, error) {
+
+	subscriptions := &SearchSubscriptions{Interval: interval, Path: path, searches: make(map[string]*SavedSearch), seen: make(map[string]*seenSet), sinks: make(map[string]Sink)}
+
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return subscriptions, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]*SavedSearch, 0)
+
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, err
+	}
+
+	for _, search := range searches {
+
+		subscriptions.searches[search.Name] = search
+		subscriptions.seen[search.Name] = newSeenSet(streamSeenLimit)
+
+		for _, id := range search.Seen {
+			subscriptions.seen[search.Name].add(id)
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// Subscribe route new matches of a search to a sink. Subscribing to a loaded search with the same subreddit and query resumes it; a different subreddit or query starts it afresh
+func (me *SearchSubscriptions) Subscribe(name, subreddit, query string, sink Sink) error {
+
+	if name == "" || query == "" {
+		return errors.New("Saved searches need a name and a query")
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if _, ok := me.sinks[name]; ok {
+		return fmt.Errorf("Already subscribed to saved search '%s'", name)
+	}
+
+	if search, ok := me.searches[name]; !ok || search.Subreddit != subreddit || search.Query != query {
+		me.searches[name] = &SavedSearch{Name: name, Subreddit: subreddit, Query: query, Seen: make([]string, 0)}
+		me.seen[name] = newSeenSet(streamSeenLimit)
+	}
+
+	me.sinks[name] = sink
+
+	return me.save()
+}
+
+// Unsubscribe stop running a saved search and forget it
+func (me *SearchSubscriptions) Unsubscribe(name string) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	delete(me.searches, name)
+	delete(me.seen, name)
+	delete(me.sinks, name)
+
+	return me.save()
+}
+
+// Searches the saved searches, including loaded searches that are not subscribed to
+func (me *SearchSubscriptions) Searches() []SavedSearch {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	searches := make([]SavedSearch, 0, len(me.searches))
+
+	for _, search := range me.searches {
+		searches = append(searches, *search)
+	}
+
+	return searches
+}
+
+// Run re-run every subscribed search until the context is cancelled, writing posts not matched before to the search's sink, oldest first, and saving the searches after every round. Search, sink and save errors do not stop the subscriptions; they are delivered on the returned channel if the consumer is receiving from it and dropped otherwise
+func (me *SearchSubscriptions) Run(ctx context.Context) <-chan error {
+
+	errs := make(chan error, 1)
+
+	go func() {
+
+		defer close(errs)
+
+		for {
+			for _, name := range me.subscribed() {
+				if err := me.poll(name); err != nil {
+					sendError(errs, fmt.Errorf("Saved search '%s': %s", name, err.Error()))
+				}
+			}
+
+			me.mutex.Lock()
+			err := me.save()
+			me.mutex.Unlock()
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			select {
+			case <-after(me.Interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return errs
+}
+
+func (me *SearchSubscriptions) subscribed() []string {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	names := make([]string, 0, len(me.sinks))
+
+	for name := range me.sinks {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (me *SearchSubscriptions) poll(name string) error {
+
+	me.mutex.Lock()
+	search, ok := me.searches[name]
+	sink := me.sinks[name]
+	me.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	posts, _, err := SearchPosts(search.Subreddit, search.Query, "")
+
+	if err != nil {
+		return err
+	}
+
+	me.mutex.Lock()
+
+	fresh := make([]Post, 0)
+
+	seen := me.seen[name]
+	primed := search.Primed
+	pending := make(map[string]bool)
+
+	for i := len(posts) - 1; i >= 0; i-- {
+		if id := posts[i].ID; !seen.ids[id] && !pending[id] {
+			pending[id] = true
+			fresh = append(fresh, posts[i])
+		}
+	}
+
+	me.mutex.Unlock()
+
+	if primed && len(fresh) > 0 {
+
+		matches := make([]interface{}, len(fresh))
+
+		for i := range fresh {
+			matches[i] = fresh[i]
+		}
+
+		if err := sink.Write(matches...); err != nil {
+			return err
+		}
+
+		if err := sink.Flush(); err != nil {
+			return err
+		}
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if me.searches[name] != search {
+		return nil
+	}
+
+	for _, post := range fresh {
+		seen.add(post.ID)
+	}
+
+	search.Seen = append([]string(nil), seen.order...)
+	search.Primed = true
+
+	return nil
+}
+
+// save write the saved searches to Path, replacing the file only once the write has succeeded. Callers hold the mutex
+func (me *SearchSubscriptions) save() error {
+
+	if me.Path == "" {
+		return nil
+	}
+
+	searches := make([]*SavedSearch, 0, len(me.searches))
+
+	for _, search := range me.searches {
+		searches = append(searches, search)
+	}
+
+	data, err := json.MarshalIndent(searches, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(me.Path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(me.Path+".tmp", me.Path)
+}
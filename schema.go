@@ -0,0 +1,148 @@
+package rscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect the JSON Schema version schemas are written in
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	schemaTimeType = reflect.TypeOf(time.Time{})
+	schemaRawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaTypes the exported types JSON Schemas are published for, by name
+var schemaTypes = map[string]interface{}{
+	"Post":      Post{},
+	"Comment":   Comment{},
+	"Subreddit": Subreddit{},
+	"Thread":    Thread{},
+}
+
+// SchemaNames the names of the types JSONSchema describes
+func SchemaNames() []string {
+
+	names := make([]string, 0, len(schemaTypes))
+
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// JSONSchema the JSON Schema of a type named by SchemaNames, describing the JSON that JSONSink writes for it. Schemas are generated from the Go types, so they always match the JSON the package produces
+func JSONSchema(name string) ([]byte, error) {
+
+	value, ok := schemaTypes[name]
+
+	if !ok {
+		return nil, fmt.Errorf("No JSON Schema for type '%s'", name)
+	}
+
+	definitions := make(map[string]interface{})
+
+	root := schemaFor(reflect.TypeOf(value), definitions)
+
+	schema := map[string]interface{}{
+		"$schema": jsonSchemaDialect,
+		"title":   name,
+		"$ref":    root["$ref"],
+		"$defs":   definitions,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor the schema of a Go type, adding the definitions of the struct types it uses
+func schemaFor(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+
+	switch {
+	case t == schemaTimeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == schemaRawType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return map[string]interface{}{"anyOf": []interface{}{schemaFor(t.Elem(), definitions), map[string]interface{}{"type": "null"}}}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": schemaFor(t.Elem(), definitions)}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": schemaFor(t.Elem(), definitions)}
+	case reflect.Struct:
+
+		if _, ok := definitions[t.Name()]; !ok {
+
+			// reserve the name first, so recursive types such as CommentNode refer to the definition being built
+			definitions[t.Name()] = nil
+
+			definitions[t.Name()] = structSchema(t, definitions)
+		}
+
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	}
+
+	return map[string]interface{}{}
+}
+
+// structSchema the schema of a struct, following encoding/json's rules for field names and omitted fields
+func structSchema(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("json"), ",")
+
+		if tag[0] == "-" {
+			continue
+		}
+
+		name := field.Name
+
+		if tag[0] != "" {
+			name = tag[0]
+		}
+
+		properties[name] = schemaFor(field.Type, definitions)
+
+		omitempty := false
+
+		for _, option := range tag[1:] {
+			omitempty = omitempty || option == "omitempty"
+		}
+
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	sort.Strings(required)
+
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
@@ -0,0 +1,59 @@
+package rscraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// SearchPosts search a subreddit, or all of reddit when subreddit is empty, for posts matching a cloudsearch query, newest first
+func SearchPosts(subreddit, query, after string) ([]Post, string, error) {
+
+	posts, after, _, err := searchPosts(subreddit, query, after)
+
+	return posts, after, err
+}
+
+// searchPosts search for posts, along with the number of results before the guardrails dropped any
+func searchPosts(subreddit, query, after string) ([]Post, string, int, error) {
+
+	redditURL := getSearchURL(subreddit, query, after)
+
+	data, err := get(redditURL.String())
+
+	if err != nil {
+		return nil, "", 0, subredditError(subreddit, err)
+	}
+
+	posts, after, listed, err := parsePostListingCount(data)
+
+	setPostProvenance(posts, newProvenance(redditURL.String(), 0, false))
+
+	return posts, after, listed, err
+}
+
+func getSearchURL(subreddit, query, after string) *url.URL {
+
+	redditURL := getBaseURL()
+
+	q := redditURL.Query()
+
+	if subreddit == "" {
+		redditURL.Path = "/search.json"
+	} else {
+		redditURL.Path = fmt.Sprintf("/r/%s/search.json", subreddit)
+		q.Set("restrict_sr", "on")
+	}
+
+	q.Set("q", query)
+	q.Set("sort", ListingTypeNew)
+	q.Set("syntax", "cloudsearch")
+
+	if ok, _ := regexp.MatchString(apiIDRegex, after); ok {
+		q.Set("after", after)
+	}
+
+	redditURL.RawQuery = q.Encode()
+
+	return redditURL
+}
@@ -0,0 +1,350 @@
+package rscraper
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// serverStreamBuffer the number of posts a server stream holds for its consumers before dropping the oldest
+	serverStreamBuffer = 1000
+
+	// serverStreamErrors the number of errors a server stream holds for its consumers before dropping the oldest
+	serverStreamErrors = 100
+
+	// serverMinStreamInterval the shortest polling interval a stream may be started with by default
+	serverMinStreamInterval = 5 * time.Second
+
+	// serverMaxStreams the number of streams that may run at once by default
+	serverMaxStreams = 20
+)
+
+var (
+	// serverPostIDRegex the post IDs the server accepts, with or without the t3_ prefix
+	serverPostIDRegex = regexp.MustCompile(`^(t3_)?[A-Za-z0-9]{1,13}$`)
+
+	// serverSubredditRegex the subreddit names the server accepts, alone or joined with + into a multireddit
+	serverSubredditRegex = regexp.MustCompile(`^[A-Za-z0-9_]{2,21}(\+[A-Za-z0-9_]{2,21})*$`)
+)
+
+// Server an HTTP API in front of the package, so several services can share one rate limited reddit client. Every request must carry the token as a bearer token. Routes:
+//
+//	GET    /posts?subreddit=S&listing=L&after=A&top=T  a page of a listing, as GetPosts
+//	GET    /threads/SUBREDDIT/POSTID                    a post with its comment tree, as GetThread
+//	GET    /streams                                     the names of running streams
+//	POST   /streams                                     start a stream of new posts, from {"name", "subreddits", "interval"}
+//	GET    /streams/NAME                                the posts a stream received since the last call, oldest first, with the errors it ran into
+//	DELETE /streams/NAME                                stop a stream
+type Server struct {
+	Token string
+
+	// MinStreamInterval the shortest polling interval a stream may be started with
+	MinStreamInterval time.Duration
+
+	// MaxStreams the most streams that may run at once, since every stream polls reddit through the shared client. Zero allows any number
+	MaxStreams int
+
+	mutex   sync.Mutex
+	streams map[string]*serverStream
+}
+
+type serverStream struct {
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+	posts  []Post
+	errors []string
+}
+
+type serverStreamRequest struct {
+	Name       string   `json:"name"`
+	Subreddits []string `json:"subreddits"`
+	Interval   string   `json:"interval"`
+}
+
+type serverPosts struct {
+	Posts  []Post   `json:"posts"`
+	After  string   `json:"after,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type serverError struct {
+	Error string `json:"error"`
+}
+
+// NewServer create a new API server authenticating requests with token
+func NewServer(token string) *Server {
+
+	return &Server{Token: token, MinStreamInterval: serverMinStreamInterval, MaxStreams: serverMaxStreams, streams: make(map[string]*serverStream)}
+}
+
+// ServeHTTP serve a single API request
+func (me *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if !me.authorized(r) {
+		writeServerJSON(w, http.StatusUnauthorized, serverError{Error: "Missing or invalid bearer token"})
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "posts" && r.Method == "GET":
+		me.getPosts(w, r)
+	case len(parts) == 3 && parts[0] == "threads" && r.Method == "GET":
+		me.getThread(w, parts[1], parts[2])
+	case len(parts) == 1 && parts[0] == "streams" && r.Method == "GET":
+		writeServerJSON(w, http.StatusOK, me.streamNames())
+	case len(parts) == 1 && parts[0] == "streams" && r.Method == "POST":
+		me.startStream(w, r)
+	case len(parts) == 2 && parts[0] == "streams" && r.Method == "GET":
+		me.readStream(w, parts[1])
+	case len(parts) == 2 && parts[0] == "streams" && r.Method == "DELETE":
+		me.stopStream(w, parts[1])
+	default:
+		writeServerJSON(w, http.StatusNotFound, serverError{Error: "Unknown route " + r.Method + " " + r.URL.Path})
+	}
+}
+
+// Close stop every running stream
+func (me *Server) Close() {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for name, stream := range me.streams {
+		stream.cancel()
+		delete(me.streams, name)
+	}
+}
+
+func (me *Server) authorized(r *http.Request) bool {
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return me.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(me.Token)) == 1
+}
+
+func (me *Server) getPosts(w http.ResponseWriter, r *http.Request) {
+
+	q := r.URL.Query()
+
+	listingType := q.Get("listing")
+
+	if listingType == "" {
+		listingType = ListingTypeNew
+	}
+
+	if !serverSubredditRegex.MatchString(q.Get("subreddit")) {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid subreddit '" + q.Get("subreddit") + "'"})
+		return
+	}
+
+	switch listingType {
+	case ListingTypeNew, ListingTypeHot, ListingTypeTop, ListingTypeRising:
+	default:
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid listing '" + listingType + "'"})
+		return
+	}
+
+	switch q.Get("top") {
+	case "", ListingTopAllTime, ListingTopPastHour, ListingTopPastDay, ListingTopPastWeek, ListingTopPastMonth, ListingTopPastYear:
+	default:
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid top period '" + q.Get("top") + "'"})
+		return
+	}
+
+	posts, after, err := GetPosts(q.Get("subreddit"), listingType, q.Get("after"), q.Get("top"))
+
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	writeServerJSON(w, http.StatusOK, serverPosts{Posts: posts, After: after})
+}
+
+func (me *Server) getThread(w http.ResponseWriter, subreddit, postID string) {
+
+	if !serverSubredditRegex.MatchString(subreddit) {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid subreddit '" + subreddit + "'"})
+		return
+	}
+
+	if !serverPostIDRegex.MatchString(postID) {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid post ID '" + postID + "'"})
+		return
+	}
+
+	thread, err := GetThread(subreddit, postID, "")
+
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	writeServerJSON(w, http.StatusOK, thread)
+}
+
+func (me *Server) streamNames() []string {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	names := make([]string, 0, len(me.streams))
+
+	for name := range me.streams {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (me *Server) startStream(w http.ResponseWriter, r *http.Request) {
+
+	var request serverStreamRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: err.Error()})
+		return
+	}
+
+	interval, err := time.ParseDuration(request.Interval)
+
+	if err != nil || interval <= 0 || request.Name == "" || len(request.Subreddits) == 0 {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Streams need a name, at least one subreddit and a positive interval such as \"30s\""})
+		return
+	}
+
+	for _, subreddit := range request.Subreddits {
+		if !serverSubredditRegex.MatchString(subreddit) {
+			writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Invalid subreddit '" + subreddit + "'"})
+			return
+		}
+	}
+
+	if interval < me.MinStreamInterval {
+		writeServerJSON(w, http.StatusBadRequest, serverError{Error: "Stream intervals must be at least " + me.MinStreamInterval.String()})
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if _, ok := me.streams[request.Name]; ok {
+		writeServerJSON(w, http.StatusConflict, serverError{Error: "Stream '" + request.Name + "' is already running"})
+		return
+	}
+
+	if me.MaxStreams > 0 && len(me.streams) >= me.MaxStreams {
+		writeServerJSON(w, http.StatusTooManyRequests, serverError{Error: "Too many streams running, stop one first"})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream := &serverStream{cancel: cancel, posts: make([]Post, 0)}
+
+	me.streams[request.Name] = stream
+
+	posts, errs := StreamPosts(ctx, interval, request.Subreddits...)
+
+	go func() {
+		for post := range posts {
+
+			stream.mutex.Lock()
+
+			stream.posts = append(stream.posts, post)
+
+			if len(stream.posts) > serverStreamBuffer {
+				stream.posts = stream.posts[len(stream.posts)-serverStreamBuffer:]
+			}
+
+			stream.mutex.Unlock()
+		}
+	}()
+
+	go func() {
+		for err := range errs {
+
+			stream.mutex.Lock()
+
+			stream.errors = append(stream.errors, Redact(err.Error()))
+
+			if len(stream.errors) > serverStreamErrors {
+				stream.errors = stream.errors[len(stream.errors)-serverStreamErrors:]
+			}
+
+			stream.mutex.Unlock()
+		}
+	}()
+
+	writeServerJSON(w, http.StatusCreated, request)
+}
+
+func (me *Server) readStream(w http.ResponseWriter, name string) {
+
+	me.mutex.Lock()
+	stream, ok := me.streams[name]
+	me.mutex.Unlock()
+
+	if !ok {
+		writeServerJSON(w, http.StatusNotFound, serverError{Error: "No stream named '" + name + "'"})
+		return
+	}
+
+	stream.mutex.Lock()
+	posts, errors := stream.posts, stream.errors
+	stream.posts, stream.errors = make([]Post, 0), nil
+	stream.mutex.Unlock()
+
+	writeServerJSON(w, http.StatusOK, serverPosts{Posts: posts, Errors: errors})
+}
+
+func (me *Server) stopStream(w http.ResponseWriter, name string) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	stream, ok := me.streams[name]
+
+	if !ok {
+		writeServerJSON(w, http.StatusNotFound, serverError{Error: "No stream named '" + name + "'"})
+		return
+	}
+
+	stream.cancel()
+	delete(me.streams, name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeServerError report an error from reddit, passing on reddit's status for unsuccessful responses
+func writeServerError(w http.ResponseWriter, err error) {
+
+	status := http.StatusBadGateway
+
+	switch value := err.(type) {
+	case *ResponseError:
+		status = value.StatusCode
+	case *SubredditStatusError:
+		status = http.StatusForbidden
+	case *GuardrailError:
+		status = http.StatusForbidden
+	}
+
+	writeServerJSON(w, status, serverError{Error: err.Error()})
+}
+
+func writeServerJSON(w http.ResponseWriter, status int, value interface{}) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,47 @@
+//go:build grpc
+// +build grpc
+
+package grpc
+
+import "fmt"
+
+type protoMarshaler interface {
+	MarshalProto() []byte
+}
+
+type protoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+// Codec a gRPC codec for the service's messages and the rscraper types that implement MarshalProto and UnmarshalProto. It is named proto, so clients using generated stubs interoperate with it
+type Codec struct{}
+
+// Marshal encode a message
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+
+	message, ok := v.(protoMarshaler)
+
+	if !ok {
+		return nil, fmt.Errorf("Unable to marshal %T as a protocol buffer message", v)
+	}
+
+	return message.MarshalProto(), nil
+}
+
+// Unmarshal decode a message
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+
+	message, ok := v.(protoUnmarshaler)
+
+	if !ok {
+		return fmt.Errorf("Unable to unmarshal %T as a protocol buffer message", v)
+	}
+
+	return message.UnmarshalProto(data)
+}
+
+// Name the content subtype of the codec
+func (Codec) Name() string {
+
+	return "proto"
+}
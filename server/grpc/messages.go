@@ -0,0 +1,245 @@
+//go:build grpc
+// +build grpc
+
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// GetPostsRequest the GetPostsRequest message of proto/scraper_service.proto
+type GetPostsRequest struct {
+	Subreddit   string
+	ListingType string
+	After       string
+	TopType     string
+}
+
+// GetPostsResponse the GetPostsResponse message of proto/scraper_service.proto
+type GetPostsResponse struct {
+	Posts []rscraper.Post
+	After string
+}
+
+// StreamPostsRequest the StreamPostsRequest message of proto/scraper_service.proto
+type StreamPostsRequest struct {
+	Subreddits      []string
+	IntervalSeconds int64
+}
+
+// GetThreadRequest the GetThreadRequest message of proto/scraper_service.proto
+type GetThreadRequest struct {
+	Subreddit string
+	PostID    string
+}
+
+// MarshalProto encode the request as a protocol buffer message
+func (me *GetPostsRequest) MarshalProto() []byte {
+
+	buf := appendString(nil, 1, me.Subreddit)
+	buf = appendString(buf, 2, me.ListingType)
+	buf = appendString(buf, 3, me.After)
+
+	return appendString(buf, 4, me.TopType)
+}
+
+// UnmarshalProto decode a protocol buffer message into the request
+func (me *GetPostsRequest) UnmarshalProto(data []byte) error {
+
+	*me = GetPostsRequest{}
+
+	return readFields(data, func(number int, varint uint64, bytes []byte) {
+
+		switch number {
+		case 1:
+			me.Subreddit = string(bytes)
+		case 2:
+			me.ListingType = string(bytes)
+		case 3:
+			me.After = string(bytes)
+		case 4:
+			me.TopType = string(bytes)
+		}
+	})
+}
+
+// MarshalProto encode the response as a protocol buffer message
+func (me *GetPostsResponse) MarshalProto() []byte {
+
+	var buf []byte
+
+	for i := range me.Posts {
+		buf = appendBytes(buf, 1, me.Posts[i].MarshalProto())
+	}
+
+	return appendString(buf, 2, me.After)
+}
+
+// UnmarshalProto decode a protocol buffer message into the response
+func (me *GetPostsResponse) UnmarshalProto(data []byte) error {
+
+	*me = GetPostsResponse{Posts: make([]rscraper.Post, 0)}
+
+	var postErr error
+
+	err := readFields(data, func(number int, varint uint64, bytes []byte) {
+
+		switch number {
+		case 1:
+
+			var post rscraper.Post
+
+			if err := post.UnmarshalProto(bytes); err != nil && postErr == nil {
+				postErr = err
+			}
+
+			me.Posts = append(me.Posts, post)
+		case 2:
+			me.After = string(bytes)
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return postErr
+}
+
+// MarshalProto encode the request as a protocol buffer message
+func (me *StreamPostsRequest) MarshalProto() []byte {
+
+	var buf []byte
+
+	for _, subreddit := range me.Subreddits {
+		buf = appendBytes(buf, 1, []byte(subreddit))
+	}
+
+	if me.IntervalSeconds != 0 {
+		buf = appendVarint(buf, 2<<3|wireVarint)
+		buf = appendVarint(buf, uint64(me.IntervalSeconds))
+	}
+
+	return buf
+}
+
+// UnmarshalProto decode a protocol buffer message into the request
+func (me *StreamPostsRequest) UnmarshalProto(data []byte) error {
+
+	*me = StreamPostsRequest{Subreddits: make([]string, 0)}
+
+	return readFields(data, func(number int, varint uint64, bytes []byte) {
+
+		switch number {
+		case 1:
+			me.Subreddits = append(me.Subreddits, string(bytes))
+		case 2:
+			me.IntervalSeconds = int64(varint)
+		}
+	})
+}
+
+// MarshalProto encode the request as a protocol buffer message
+func (me *GetThreadRequest) MarshalProto() []byte {
+
+	buf := appendString(nil, 1, me.Subreddit)
+
+	return appendString(buf, 2, me.PostID)
+}
+
+// UnmarshalProto decode a protocol buffer message into the request
+func (me *GetThreadRequest) UnmarshalProto(data []byte) error {
+
+	*me = GetThreadRequest{}
+
+	return readFields(data, func(number int, varint uint64, bytes []byte) {
+
+		switch number {
+		case 1:
+			me.Subreddit = string(bytes)
+		case 2:
+			me.PostID = string(bytes)
+		}
+	})
+}
+
+// appendString append a string field, leaving it out when empty as proto3 does
+func appendString(buf []byte, field int, value string) []byte {
+
+	if value == "" {
+		return buf
+	}
+
+	return appendBytes(buf, field, []byte(value))
+}
+
+func appendVarint(buf []byte, value uint64) []byte {
+
+	for value >= 0x80 {
+		buf = append(buf, byte(value)|0x80)
+		value >>= 7
+	}
+
+	return append(buf, byte(value))
+}
+
+func appendBytes(buf []byte, field int, value []byte) []byte {
+
+	buf = appendVarint(buf, uint64(field)<<3|wireBytes)
+	buf = appendVarint(buf, uint64(len(value)))
+
+	return append(buf, value...)
+}
+
+// readFields call fn with each varint and length-delimited field of a message. Fields of other wire types are rejected, as the service's messages do not use them
+func readFields(data []byte, fn func(number int, varint uint64, bytes []byte)) error {
+
+	for len(data) > 0 {
+
+		key, n := binary.Uvarint(data)
+
+		if n <= 0 {
+			return errors.New("Invalid protocol buffer field key")
+		}
+
+		data = data[n:]
+
+		switch key & 7 {
+		case wireVarint:
+
+			value, n := binary.Uvarint(data)
+
+			if n <= 0 {
+				return errors.New("Invalid protocol buffer varint")
+			}
+
+			data = data[n:]
+
+			fn(int(key>>3), value, nil)
+		case wireBytes:
+
+			length, n := binary.Uvarint(data)
+
+			if n <= 0 || uint64(len(data)-n) < length {
+				return errors.New("Truncated protocol buffer field")
+			}
+
+			fn(int(key>>3), 0, data[n:n+int(length)])
+
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("Unsupported protocol buffer wire type %d", key&7)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+//go:build grpc
+// +build grpc
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/littlehawk93/rscraper"
+)
+
+func TestMessagesRoundTrip(t *testing.T) {
+
+	codec := Codec{}
+
+	request := &StreamPostsRequest{Subreddits: []string{"golang", "rust"}, IntervalSeconds: 45}
+
+	data, err := codec.Marshal(request)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded StreamPostsRequest
+
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Subreddits) != 2 || decoded.Subreddits[1] != "rust" || decoded.IntervalSeconds != 45 {
+		t.Fatalf("Unexpected request after round trip: %+v", decoded)
+	}
+
+	response := &GetPostsResponse{Posts: []rscraper.Post{{ID: "abc123", Title: "A post"}, {ID: "def456"}}, After: "t3_def456"}
+
+	data, err = codec.Marshal(response)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var page GetPostsResponse
+
+	if err := codec.Unmarshal(data, &page); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Posts) != 2 || page.Posts[0].Title != "A post" || page.After != "t3_def456" {
+		t.Fatalf("Unexpected response after round trip: %+v", page)
+	}
+
+	if _, err := codec.Marshal("not a message"); err == nil {
+		t.Fatal("Expected marshalling a non-message to fail")
+	}
+}
+
+func TestGetThreadRejectsInvalidPostIDs(t *testing.T) {
+
+	server := NewServer()
+
+	for _, id := range []string{"", "a/b", "t3_", "abc123def456ghi789"} {
+		if _, err := server.GetThread(context.Background(), &GetThreadRequest{Subreddit: "golang", PostID: id}); err == nil {
+			t.Errorf("Expected post ID %q to be rejected", id)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+//go:build grpc
+// +build grpc
+
+// Package grpc serves the scraper over gRPC, implementing the Scraper service of proto/scraper_service.proto so services written in other languages can run the scraper as a sidecar. It depends on google.golang.org/grpc and is only built with the grpc build tag:
+//
+//	go get google.golang.org/grpc
+//	go build -tags grpc ./server/grpc
+//
+// Since google.golang.org/grpc is not a dependency of the rscraper package itself, builds without the tag, including go vet ./... and go test ./..., skip this package; check it with go vet -tags grpc ./server/grpc after fetching the dependency.
+//
+// Messages are encoded by the proto.go encoders of the rscraper package rather than generated code, so clients generate their stubs from the .proto files as usual. A minimal sidecar:
+//
+//	listener, err := net.Listen("tcp", "127.0.0.1:9090")
+//	...
+//	server := grpc.NewGRPCServer()
+//	server.Serve(listener)
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/littlehawk93/rscraper"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultStreamInterval the polling interval of StreamPosts calls that do not set one
+	DefaultStreamInterval = 30 * time.Second
+
+	// DefaultMinStreamInterval the shortest polling interval StreamPosts calls may request by default
+	DefaultMinStreamInterval = 5 * time.Second
+
+	serviceName = "rscraper.Scraper"
+)
+
+var postIDRegex = regexp.MustCompile(`^(t3_)?[A-Za-z0-9]{1,13}$`)
+
+// Server implements the Scraper service by calling GetPosts, StreamPosts and GetThread
+type Server struct {
+
+	// MinStreamInterval the shortest polling interval a StreamPosts call may request. Shorter intervals are raised to it
+	MinStreamInterval time.Duration
+}
+
+// NewServer create a new Scraper service implementation
+func NewServer() *Server {
+
+	return &Server{MinStreamInterval: DefaultMinStreamInterval}
+}
+
+// NewGRPCServer create a gRPC server with the Scraper service registered and the codec its messages need
+func NewGRPCServer(opts ...grpclib.ServerOption) *grpclib.Server {
+
+	server := grpclib.NewServer(append(opts, grpclib.ForceServerCodec(Codec{}))...)
+
+	NewServer().Register(server)
+
+	return server
+}
+
+// Register add the Scraper service to a gRPC server. The server must be created with the ForceServerCodec option set to Codec
+func (me *Server) Register(server *grpclib.Server) {
+
+	server.RegisterService(&serviceDesc, me)
+}
+
+// GetPosts return one page of a subreddit listing
+func (me *Server) GetPosts(ctx context.Context, request *GetPostsRequest) (*GetPostsResponse, error) {
+
+	if request.Subreddit == "" {
+		return nil, status.Error(codes.InvalidArgument, "A subreddit is required")
+	}
+
+	listingType := request.ListingType
+
+	if listingType == "" {
+		listingType = rscraper.ListingTypeNew
+	}
+
+	posts, after, err := rscraper.GetPosts(request.Subreddit, listingType, request.After, request.TopType)
+
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &GetPostsResponse{Posts: posts, After: after}, nil
+}
+
+// StreamPosts send new posts from the requested subreddits until the client cancels the call. Errors that retrying cannot fix, such as a banned subreddit, end the call; others are retried by the stream
+func (me *Server) StreamPosts(request *StreamPostsRequest, stream grpclib.ServerStream) error {
+
+	if len(request.Subreddits) == 0 {
+		return status.Error(codes.InvalidArgument, "At least one subreddit is required")
+	}
+
+	interval := time.Duration(request.IntervalSeconds) * time.Second
+
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+
+	if interval < me.MinStreamInterval {
+		interval = me.MinStreamInterval
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	posts, errs := rscraper.StreamPosts(ctx, interval, request.Subreddits...)
+
+	for {
+		select {
+		case post, ok := <-posts:
+
+			if !ok {
+				return stream.Context().Err()
+			}
+
+			if err := stream.SendMsg(&post); err != nil {
+				return err
+			}
+		case err, ok := <-errs:
+
+			if !ok {
+				errs = nil
+				continue
+			}
+
+			if permanent(err) {
+				return statusError(err)
+			}
+		}
+	}
+}
+
+// GetThread return a post with its comment tree
+func (me *Server) GetThread(ctx context.Context, request *GetThreadRequest) (*rscraper.Thread, error) {
+
+	if request.Subreddit == "" || !postIDRegex.MatchString(request.PostID) {
+		return nil, status.Error(codes.InvalidArgument, "A subreddit and a valid post ID are required")
+	}
+
+	thread, err := rscraper.GetThread(request.Subreddit, request.PostID, "")
+
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return thread, nil
+}
+
+// permanent whether a stream error will keep recurring however often the stream retries
+func permanent(err error) bool {
+
+	switch value := err.(type) {
+	case *rscraper.SubredditStatusError, *rscraper.GuardrailError:
+		return true
+	case *rscraper.ResponseError:
+		return value.StatusCode != http.StatusTooManyRequests && value.StatusCode < 500
+	}
+
+	return false
+}
+
+// statusError the gRPC status of an error from reddit
+func statusError(err error) error {
+
+	code := codes.Unavailable
+
+	switch value := err.(type) {
+	case *rscraper.SubredditStatusError:
+		code = codes.FailedPrecondition
+
+		if value.Status == rscraper.SubredditStatusNotFound {
+			code = codes.NotFound
+		}
+	case *rscraper.GuardrailError:
+		code = codes.PermissionDenied
+	case *rscraper.ResponseError:
+
+		switch {
+		case value.StatusCode == http.StatusNotFound:
+			code = codes.NotFound
+		case value.StatusCode == http.StatusForbidden || value.StatusCode == http.StatusUnauthorized:
+			code = codes.PermissionDenied
+		case value.StatusCode == http.StatusTooManyRequests:
+			code = codes.ResourceExhausted
+		case value.StatusCode < 500:
+			code = codes.InvalidArgument
+		}
+	}
+
+	return status.Error(code, rscraper.Redact(err.Error()))
+}
+
+var serviceDesc = grpclib.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpclib.MethodDesc{
+		{MethodName: "GetPosts", Handler: getPostsHandler},
+		{MethodName: "GetThread", Handler: getThreadHandler},
+	},
+	Streams: []grpclib.StreamDesc{
+		{StreamName: "StreamPosts", Handler: streamPostsHandler, ServerStreams: true},
+	},
+	Metadata: "scraper_service.proto",
+}
+
+func getPostsHandler(srv interface{}, ctx context.Context, decode func(interface{}) error, interceptor grpclib.UnaryServerInterceptor) (interface{}, error) {
+
+	request := &GetPostsRequest{}
+
+	if err := decode(request); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return srv.(*Server).GetPosts(ctx, request.(*GetPostsRequest))
+	}
+
+	if interceptor == nil {
+		return handler(ctx, request)
+	}
+
+	return interceptor(ctx, request, &grpclib.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/GetPosts"}, handler)
+}
+
+func getThreadHandler(srv interface{}, ctx context.Context, decode func(interface{}) error, interceptor grpclib.UnaryServerInterceptor) (interface{}, error) {
+
+	request := &GetThreadRequest{}
+
+	if err := decode(request); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return srv.(*Server).GetThread(ctx, request.(*GetThreadRequest))
+	}
+
+	if interceptor == nil {
+		return handler(ctx, request)
+	}
+
+	return interceptor(ctx, request, &grpclib.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/GetThread"}, handler)
+}
+
+func streamPostsHandler(srv interface{}, stream grpclib.ServerStream) error {
+
+	request := &StreamPostsRequest{}
+
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+
+	return srv.(*Server).StreamPosts(request, stream)
+}
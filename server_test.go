@@ -0,0 +1,59 @@
+package rscraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerRejectsInvalidRequests(t *testing.T) {
+
+	server := NewServer("secret")
+	defer server.Close()
+
+	requests := map[string]*http.Request{
+		"post ID":          httptest.NewRequest("GET", "/threads/golang/a.b", nil),
+		"thread subreddit": httptest.NewRequest("GET", "/threads/go.lang/abc123", nil),
+		"subreddit":        httptest.NewRequest("GET", "/posts?subreddit=golang/../x", nil),
+		"listing":          httptest.NewRequest("GET", "/posts?subreddit=golang&listing=..", nil),
+		"top":              httptest.NewRequest("GET", "/posts?subreddit=golang&listing=top&top=x%26y", nil),
+		"stream subreddit": httptest.NewRequest("POST", "/streams", strings.NewReader(`{"name":"bad","subreddits":["a/b"],"interval":"1m"}`)),
+		"stream interval":  httptest.NewRequest("POST", "/streams", strings.NewReader(`{"name":"fast","subreddits":["golang"],"interval":"1s"}`)),
+	}
+
+	for name, request := range requests {
+
+		request.Header.Set("Authorization", "Bearer secret")
+
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", name, http.StatusBadRequest, recorder.Code)
+		}
+	}
+}
+
+func TestServerLimitsStreams(t *testing.T) {
+
+	server := NewServer("secret")
+	defer server.Close()
+
+	server.MaxStreams = 1
+
+	for i, expected := range []int{http.StatusCreated, http.StatusTooManyRequests} {
+
+		request := httptest.NewRequest("POST", "/streams", strings.NewReader(`{"name":"s`+string(rune('a'+i))+`","subreddits":["golang"],"interval":"1h"}`))
+		request.Header.Set("Authorization", "Bearer secret")
+
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+
+		if recorder.Code != expected {
+			t.Errorf("Stream %d: expected status %d, got %d", i+1, expected, recorder.Code)
+		}
+	}
+}
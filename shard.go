@@ -0,0 +1,166 @@
+package rscraper
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// shardReplicas the number of points each instance is given on the hash ring by default. More points spread subreddits more evenly
+const shardReplicas = 128
+
+// Sharder splits a list of subreddits between several crawler instances by consistent hashing. Every instance configured with the same total computes the same assignment, and when the total changes only about 1/total of the subreddits move to a different instance
+type Sharder struct {
+	Index int
+	Total int
+
+	ring []shardPoint
+}
+
+type shardPoint struct {
+	hash     uint64
+	instance int
+}
+
+// ShardMove a subreddit whose owning instance changes when the number of instances changes
+type ShardMove struct {
+	Subreddit string
+	From      int
+	To        int
+}
+
+// NewSharder create a new sharder for instance index, counting from zero, of total instances
+func NewSharder(index, total int) (*Sharder, error) {
+
+	if total < 1 {
+		return nil, fmt.Errorf("Invalid shard total %d, must be at least 1", total)
+	}
+
+	if index < 0 || index >= total {
+		return nil, fmt.Errorf("Invalid shard index %d, must be between 0 and %d", index, total-1)
+	}
+
+	ring := make([]shardPoint, 0, total*shardReplicas)
+
+	for instance := 0; instance < total; instance++ {
+		for replica := 0; replica < shardReplicas; replica++ {
+			ring = append(ring, shardPoint{hash: shardHash(fmt.Sprintf("instance-%d-%d", instance, replica)), instance: instance})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].hash < ring[j].hash
+	})
+
+	return &Sharder{Index: index, Total: total, ring: ring}, nil
+}
+
+// ShardFromEnv create a sharder from the RSCRAPE_SHARD_INDEX and RSCRAPE_SHARD_TOTAL environment variables. When neither is set the sharder is a single instance owning every subreddit
+func ShardFromEnv() (*Sharder, error) {
+
+	index, total := os.Getenv("RSCRAPE_SHARD_INDEX"), os.Getenv("RSCRAPE_SHARD_TOTAL")
+
+	if index == "" && total == "" {
+		return NewSharder(0, 1)
+	}
+
+	i, err := strconv.Atoi(index)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid RSCRAPE_SHARD_INDEX '%s'", index)
+	}
+
+	n, err := strconv.Atoi(total)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid RSCRAPE_SHARD_TOTAL '%s'", total)
+	}
+
+	return NewSharder(i, n)
+}
+
+// Owner the index of the instance that owns a subreddit. Subreddit names are compared case insensitively
+func (me *Sharder) Owner(subreddit string) int {
+
+	hash := shardHash(strings.ToLower(subreddit))
+
+	i := sort.Search(len(me.ring), func(i int) bool {
+		return me.ring[i].hash >= hash
+	})
+
+	if i == len(me.ring) {
+		i = 0
+	}
+
+	return me.ring[i].instance
+}
+
+// Owns whether this instance owns a subreddit
+func (me *Sharder) Owns(subreddit string) bool {
+
+	return me.Owner(subreddit) == me.Index
+}
+
+// Assign the subreddits this instance owns, in their original order
+func (me *Sharder) Assign(subreddits []string) []string {
+
+	owned := make([]string, 0, len(subreddits)/me.Total+1)
+
+	for _, subreddit := range subreddits {
+		if me.Owns(subreddit) {
+			owned = append(owned, subreddit)
+		}
+	}
+
+	return owned
+}
+
+// Rebalance the subreddits that change owner when the number of instances changes to total. Use it to hand over state, such as stream cursors, before resizing a deployment
+func (me *Sharder) Rebalance(subreddits []string, total int) ([]ShardMove, error) {
+
+	index := me.Index
+
+	if index >= total {
+		index = 0
+	}
+
+	resized, err := NewSharder(index, total)
+
+	if err != nil {
+		return nil, err
+	}
+
+	moves := make([]ShardMove, 0)
+
+	for _, subreddit := range subreddits {
+
+		from, to := me.Owner(subreddit), resized.Owner(subreddit)
+
+		if from != to {
+			moves = append(moves, ShardMove{Subreddit: subreddit, From: from, To: to})
+		}
+	}
+
+	return moves, nil
+}
+
+// shardHash an FNV-1a hash with a final avalanche step, as FNV alone clusters similar short keys such as replica names
+func shardHash(key string) uint64 {
+
+	hash := fnv.New64a()
+
+	hash.Write([]byte(key))
+
+	value := hash.Sum64()
+
+	value ^= value >> 33
+	value *= 0xff51afd7ed558ccd
+	value ^= value >> 33
+	value *= 0xc4ceb9fe1a85ec53
+	value ^= value >> 33
+
+	return value
+}
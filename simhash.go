@@ -0,0 +1,230 @@
+package rscraper
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// simhashDefaultShingleSize the number of consecutive words hashed together by default
+	simhashDefaultShingleSize = 3
+
+	// simhashMaxDistance the largest Hamming distance an index can search for. Larger distances match unrelated texts
+	simhashMaxDistance = 15
+)
+
+// Simhash the 64 bit simhash of a text's shingles, runs of shingleSize consecutive normalized words. Texts that share most of their shingles have hashes that differ in few bits. Texts shorter than a shingle are hashed as a single shingle
+func Simhash(text string, shingleSize int) uint64 {
+
+	if shingleSize < 1 {
+		shingleSize = simhashDefaultShingleSize
+	}
+
+	words := strings.Fields(NormalizeCommentBody(text))
+
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+
+	for start := 0; start == 0 || start+shingleSize <= len(words); start++ {
+
+		end := start + shingleSize
+
+		if end > len(words) {
+			end = len(words)
+		}
+
+		hash := fnv.New64a()
+
+		hash.Write([]byte(strings.Join(words[start:end], " ")))
+
+		sum := hash.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var result uint64
+
+	for bit, weight := range weights {
+		if weight > 0 {
+			result |= 1 << uint(bit)
+		}
+	}
+
+	return result
+}
+
+// HammingDistance the number of bits in which two simhashes differ
+func HammingDistance(a, b uint64) int {
+
+	return bits.OnesCount64(a ^ b)
+}
+
+// NearDuplicateIndex clusters texts whose simhashes are within a Hamming distance of each other. Candidates are found by splitting hashes into bands, one more than the distance, of which near duplicates must share at least one, so texts are not compared pairwise
+type NearDuplicateIndex struct {
+	MaxDistance int
+	ShingleSize int
+
+	mutex  sync.Mutex
+	keys   []string
+	hashes []uint64
+	parent []int
+	bands  []map[uint64][]int
+}
+
+// NewNearDuplicateIndex create a new index clustering texts whose simhashes differ in at most maxDistance bits, up to 15. A distance of 3 is a reasonable start for short texts such as titles
+func NewNearDuplicateIndex(maxDistance int) *NearDuplicateIndex {
+
+	if maxDistance < 0 {
+		maxDistance = 0
+	}
+
+	if maxDistance > simhashMaxDistance {
+		maxDistance = simhashMaxDistance
+	}
+
+	bands := make([]map[uint64][]int, maxDistance+1)
+
+	for i := range bands {
+		bands[i] = make(map[uint64][]int)
+	}
+
+	return &NearDuplicateIndex{MaxDistance: maxDistance, ShingleSize: simhashDefaultShingleSize, bands: bands}
+}
+
+// Add index a text under a key, joining it to the cluster of every indexed text it is a near duplicate of. Empty texts are ignored
+func (me *NearDuplicateIndex) Add(key, text string) {
+
+	if NormalizeCommentBody(text) == "" {
+		return
+	}
+
+	hash := Simhash(text, me.ShingleSize)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	index := len(me.keys)
+
+	me.keys = append(me.keys, key)
+	me.hashes = append(me.hashes, hash)
+	me.parent = append(me.parent, index)
+
+	for band := range me.bands {
+
+		value := me.band(hash, band)
+
+		for _, candidate := range me.bands[band][value] {
+			if HammingDistance(hash, me.hashes[candidate]) <= me.MaxDistance {
+				me.union(index, candidate)
+			}
+		}
+
+		me.bands[band][value] = append(me.bands[band][value], index)
+	}
+}
+
+// Clusters the keys of every group of two or more near duplicate texts, largest first. Clusters are transitive: two texts can share a cluster through a third text close to both
+func (me *NearDuplicateIndex) Clusters() [][]string {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	groups := make(map[int][]string)
+
+	for i, key := range me.keys {
+		root := me.find(i)
+		groups[root] = append(groups[root], key)
+	}
+
+	clusters := make([][]string, 0)
+
+	for _, group := range groups {
+		if len(group) > 1 {
+			clusters = append(clusters, group)
+		}
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+
+		return clusters[i][0] < clusters[j][0]
+	})
+
+	return clusters
+}
+
+// band the bits of a hash in one of the index's bands
+func (me *NearDuplicateIndex) band(hash uint64, band int) uint64 {
+
+	width := 64 / len(me.bands)
+
+	start := band * width
+
+	if band == len(me.bands)-1 {
+		width = 64 - start
+	}
+
+	return (hash >> uint(start)) & (1<<uint(width) - 1)
+}
+
+func (me *NearDuplicateIndex) find(i int) int {
+
+	for me.parent[i] != i {
+		me.parent[i] = me.parent[me.parent[i]]
+		i = me.parent[i]
+	}
+
+	return i
+}
+
+func (me *NearDuplicateIndex) union(a, b int) {
+
+	if rootA, rootB := me.find(a), me.find(b); rootA != rootB {
+		me.parent[rootA] = rootB
+	}
+}
+
+// ClusterPosts group posts whose titles and bodies together are near duplicates, see NearDuplicateIndex
+func ClusterPosts(posts []Post, maxDistance int) [][]Post {
+
+	index := NewNearDuplicateIndex(maxDistance)
+
+	byFullname := make(map[string]Post, len(posts))
+
+	for _, post := range posts {
+
+		byFullname[post.Fullname()] = post
+
+		index.Add(post.Fullname(), post.Title+"\n"+post.Text)
+	}
+
+	clusters := make([][]Post, 0)
+
+	for _, keys := range index.Clusters() {
+
+		cluster := make([]Post, 0, len(keys))
+
+		for _, key := range keys {
+			cluster = append(cluster, byFullname[key])
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
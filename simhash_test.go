@@ -0,0 +1,102 @@
+package rscraper
+
+import "testing"
+
+func TestSimhash(t *testing.T) {
+
+	text := "The quick brown fox jumps over the lazy dog near the river bank today"
+
+	tests := []struct {
+		name     string
+		other    string
+		distance int
+	}{
+		{"identical", text, 0},
+		{"normalized", "the QUICK brown fox, jumps over the lazy dog... near the river bank today!", 0},
+		{"one word changed", "The quick brown fox jumps over the lazy cat near the river bank today", 20},
+	}
+
+	for _, test := range tests {
+		if distance := HammingDistance(Simhash(text, 0), Simhash(test.other, 0)); distance > test.distance {
+			t.Errorf("%s: expected a distance of at most %d, got %d", test.name, test.distance, distance)
+		}
+	}
+
+	if Simhash("", 3) != 0 || Simhash("   ", 3) != 0 {
+		t.Error("Expected empty texts to hash to 0")
+	}
+
+	if Simhash("two words", 3) == 0 {
+		t.Error("Expected a text shorter than a shingle to be hashed")
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+
+	tests := []struct {
+		a, b     uint64
+		distance int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, ^uint64(0), 64},
+	}
+
+	for _, test := range tests {
+		if distance := HammingDistance(test.a, test.b); distance != test.distance {
+			t.Errorf("%x, %x: expected %d, got %d", test.a, test.b, test.distance, distance)
+		}
+	}
+}
+
+func TestNearDuplicateIndex(t *testing.T) {
+
+	index := NewNearDuplicateIndex(3)
+
+	index.Add("a", "Selling my old graphics card, barely used, shipping included, message me for details")
+	index.Add("b", "selling my old graphics card - barely used - shipping included - message me for details")
+	index.Add("c", "What is the best way to learn Go coming from a Python background?")
+	index.Add("d", "")
+
+	clusters := index.Clusters()
+
+	if len(clusters) != 1 || len(clusters[0]) != 2 {
+		t.Fatalf("Expected a single cluster of a and b, got %v", clusters)
+	}
+
+	if NewNearDuplicateIndex(100).MaxDistance != simhashMaxDistance || NewNearDuplicateIndex(-1).MaxDistance != 0 {
+		t.Error("Expected the distance to be clamped")
+	}
+}
+
+func TestDuplicateDetector(t *testing.T) {
+
+	detector := NewDuplicateDetector()
+
+	body := "Check out this amazing deal at the link in my profile"
+
+	detector.Write(
+		Comment{ID: "c1", PostID: "t3_p1", Author: "spam1", Body: body},
+		&Comment{ID: "c2", PostID: "t3_p2", Author: "spam2", Body: "CHECK OUT this amazing deal, at the link in my profile!!"},
+		Comment{ID: "c2", PostID: "t3_p2", Author: "spam2", Body: body},
+		Comment{ID: "c3", PostID: "t3_p1", Author: "spam1", Body: "thanks"},
+		Comment{ID: "c4", PostID: "t3_p1", Author: "someone", Body: "thanks"},
+		Comment{ID: "c5", PostID: "t3_p1", Author: "[deleted]", Body: "[deleted]"},
+		Comment{ID: "c6", PostID: "t3_p1", Author: "[deleted]", Body: "[deleted]"},
+		Comment{ID: "c7", PostID: "t3_p3", Author: "a", Body: "The same longer reply posted twice in one thread"},
+		Comment{ID: "c8", PostID: "t3_p3", Author: "b", Body: "The same longer reply posted twice in one thread"},
+	)
+
+	duplicates := detector.Duplicates(false)
+
+	if len(duplicates) != 2 {
+		t.Fatalf("Expected 2 groups of duplicates, got %d", len(duplicates))
+	}
+
+	across := detector.Duplicates(true)
+
+	if len(across) != 1 || len(across[0].Comments) != 2 || len(across[0].Authors) != 2 || len(across[0].Threads) != 2 {
+		t.Fatalf("Expected one group across threads with 2 comments, authors and threads, got %+v", across)
+	}
+}
@@ -0,0 +1,207 @@
+package rscraper
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Sink a destination for scraped items such as Posts and Comments
+type Sink interface {
+	Write(items ...interface{}) error
+	Flush() error
+}
+
+// JSONSink writes items as newline-delimited JSON
+type JSONSink struct {
+	mutex   sync.Mutex
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewJSONSink create a new sink writing newline-delimited JSON to w
+func NewJSONSink(w io.Writer) *JSONSink {
+
+	writer := bufio.NewWriter(w)
+
+	return &JSONSink{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// Write encode each item as a single line of JSON
+func (me *JSONSink) Write(items ...interface{}) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, item := range items {
+		if err := me.encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush write any buffered output to the underlying writer
+func (me *JSONSink) Flush() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.writer.Flush()
+}
+
+// batchSinkMaxBatches the number of batches a BatchSink buffers by default while the underlying sink is failing
+const batchSinkMaxBatches = 10
+
+// BatchSink buffers items and writes them to another sink in batches, once a batch is full or a flush interval has elapsed. A batch the underlying sink fails to write stays buffered and is written again by the next Write, Flush or interval
+type BatchSink struct {
+
+	// MaxBuffered the most items the sink buffers while the underlying sink is failing. Writes that would buffer more are refused with a BatchSinkFullError. Defaults to ten batches
+	MaxBuffered int
+
+	mutex    sync.Mutex
+	sink     Sink
+	size     int
+	buffer   []interface{}
+	err      error
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBatchSink create a new sink writing batches of up to size items to sink. If interval is positive, buffered items are also written whenever interval elapses. Call Close when done to stop the interval timer and write the remaining items
+func NewBatchSink(sink Sink, size int, interval time.Duration) *BatchSink {
+
+	if size < 1 {
+		size = 1
+	}
+
+	result := &BatchSink{MaxBuffered: size * batchSinkMaxBatches, sink: sink, size: size, buffer: make([]interface{}, 0, size), stop: make(chan struct{})}
+
+	if interval > 0 {
+		go result.flushEvery(interval)
+	}
+
+	return result
+}
+
+// BatchSinkFullError a write refused by a BatchSink because the underlying sink has been failing and the buffer is full. None of the items were buffered
+type BatchSinkFullError struct {
+	Buffered int
+	Items    int
+}
+
+func (me *BatchSinkFullError) Error() string {
+
+	return fmt.Sprintf("Batch sink buffer is full: %d items buffered, %d more refused", me.Buffered, me.Items)
+}
+
+// Write buffer all of the items, then write the buffer to the underlying sink if it holds a full batch. Either every item is buffered or, with a BatchSinkFullError, none is; any other error comes from writing to the underlying sink, after the items were buffered, and the items are written again later. Also returns the error of a failed background write, once, if there was one since the last Write or Flush
+func (me *BatchSink) Write(items ...interface{}) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if me.MaxBuffered > 0 && len(me.buffer)+len(items) > me.MaxBuffered {
+
+		if err := me.writeBatch(); err != nil {
+			return &BatchSinkFullError{Buffered: len(me.buffer), Items: len(items)}
+		}
+	}
+
+	me.buffer = append(me.buffer, items...)
+
+	if len(me.buffer) >= me.size {
+		if err := me.writeBatch(); err != nil {
+			return err
+		}
+	}
+
+	return me.backgroundErr()
+}
+
+// Flush write all buffered items to the underlying sink and flush it
+func (me *BatchSink) Flush() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.err = nil
+
+	return me.flush()
+}
+
+// Close stop the flush interval timer and flush all buffered items
+func (me *BatchSink) Close() error {
+
+	me.stopOnce.Do(func() {
+		close(me.stop)
+	})
+
+	return me.Flush()
+}
+
+// flush write the buffered items and flush the underlying sink. Callers hold the mutex
+func (me *BatchSink) flush() error {
+
+	if err := me.writeBatch(); err != nil {
+		return err
+	}
+
+	return me.sink.Flush()
+}
+
+// writeBatch write the buffered items in batches of up to size, keeping the items of a batch that fails to write buffered. Callers hold the mutex
+func (me *BatchSink) writeBatch() error {
+
+	for len(me.buffer) > 0 {
+
+		batch := me.buffer
+
+		if len(batch) > me.size {
+			batch = batch[:me.size]
+		}
+
+		if err := me.sink.Write(batch...); err != nil {
+			return err
+		}
+
+		me.buffer = me.buffer[len(batch):]
+	}
+
+	me.buffer = make([]interface{}, 0, me.size)
+
+	return nil
+}
+
+// backgroundErr the error of the last failed background flush, clearing it. Callers hold the mutex
+func (me *BatchSink) backgroundErr() error {
+
+	err := me.err
+
+	me.err = nil
+
+	return err
+}
+
+func (me *BatchSink) flushEvery(interval time.Duration) {
+
+	for {
+		select {
+		case <-after(interval):
+
+			me.mutex.Lock()
+
+			if err := me.flush(); err != nil {
+				me.err = err
+			}
+
+			me.mutex.Unlock()
+		case <-me.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package rscraper
+
+import (
+	"errors"
+	"testing"
+)
+
+type failingSink struct {
+	fail    bool
+	written []interface{}
+}
+
+func (me *failingSink) Write(items ...interface{}) error {
+
+	if me.fail {
+		return errors.New("Write failed")
+	}
+
+	me.written = append(me.written, items...)
+
+	return nil
+}
+
+func (me *failingSink) Flush() error {
+
+	return nil
+}
+
+func TestBatchSinkKeepsFailedBatch(t *testing.T) {
+
+	sink := &failingSink{fail: true}
+
+	batch := NewBatchSink(sink, 2, 0)
+
+	if err := batch.Write(1, 2); err == nil {
+		t.Fatal("Expected the failed batch write to be reported")
+	}
+
+	sink.fail = false
+
+	if err := batch.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.written) != 2 {
+		t.Fatalf("Wrote %d items after retrying, want 2", len(sink.written))
+	}
+
+	if err := batch.Write(3); err != nil {
+		t.Fatalf("A recovered sink still reports an error: %v", err)
+	}
+}
+
+func TestBatchSinkAcceptsWholeWrites(t *testing.T) {
+
+	sink := &failingSink{fail: true}
+
+	batch := NewBatchSink(sink, 2, 0)
+
+	batch.MaxBuffered = 4
+
+	if err := batch.Write(1, 2, 3); err == nil {
+		t.Fatal("Expected the failed batch write to be reported")
+	}
+
+	err := batch.Write(4, 5)
+
+	if _, ok := err.(*BatchSinkFullError); !ok {
+		t.Fatalf("Expected a full buffer to refuse the write, got %v", err)
+	}
+
+	sink.fail = false
+
+	if err := batch.Write(4, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := batch.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.written) != 5 {
+		t.Fatalf("Wrote %v, want every item once", sink.written)
+	}
+
+	for i, item := range sink.written {
+		if item != i+1 {
+			t.Fatalf("Wrote %v, want every item once in order", sink.written)
+		}
+	}
+}
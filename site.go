@@ -0,0 +1,277 @@
+package rscraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// siteNoFlair the index heading for posts without link flair
+const siteNoFlair = "No flair"
+
+type siteIndex struct {
+	Title    string
+	Root     string
+	Sections []siteSection
+	Flairs   []siteLink
+}
+
+type siteSection struct {
+	Heading string
+	Posts   []Post
+}
+
+type siteLink struct {
+	Name  string
+	Path  string
+	Count int
+}
+
+var siteIndexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{
+	"time":       renderTime,
+	"threadPage": siteThreadPage,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; color: #222; }
+.meta { color: #777; font-size: 0.85em; }
+li { margin-bottom: 0.4em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Flairs}}<p>{{range .Flairs}}<a href="{{$.Root}}{{.Path}}">{{.Name}}</a> ({{.Count}}) {{end}}</p>{{end}}
+{{range .Sections}}<h2>{{.Heading}}</h2>
+<ul>
+{{range .Posts}}<li><a href="{{$.Root}}threads/{{threadPage .ID}}">{{.Title}}</a> <span class="meta">{{.Author}} &middot; {{.Score}} points &middot; {{time .CreatedOn}}{{if .LinkFlairText}} &middot; {{.LinkFlairText}}{{end}}</span></li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// ExportSite write the latest snapshot of every post in a store, with its comments, as a browsable static HTML site in dir. The site has an index of posts grouped by month, an index per link flair and a page per thread rendered by RenderHTML. Returns the number of thread pages written
+func ExportSite(store Store, dir string) (int, error) {
+
+	threads, err := siteThreads(store)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "threads"), 0755); err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "flair"), 0755); err != nil {
+		return 0, err
+	}
+
+	posts := make([]Post, 0, len(threads))
+	flairs := make(map[string][]Post)
+
+	for _, thread := range threads {
+
+		if err := writeSiteFile(filepath.Join(dir, "threads", siteThreadPage(thread.Post.ID)), func(file *os.File) error {
+			return renderHTMLPage(file, thread, "../index.html")
+		}); err != nil {
+			return 0, err
+		}
+
+		posts = append(posts, thread.Post)
+
+		flair := thread.Post.LinkFlairText
+
+		if flair == "" {
+			flair = siteNoFlair
+		}
+
+		flairs[flair] = append(flairs[flair], thread.Post)
+	}
+
+	links := make([]siteLink, 0, len(flairs))
+
+	for flair, flairPosts := range flairs {
+
+		link := siteLink{Name: flair, Path: "flair/" + siteSlug(flair) + ".html", Count: len(flairPosts)}
+
+		index := &siteIndex{Title: flair, Root: "../", Sections: siteSections(flairPosts)}
+
+		if err := writeSiteFile(filepath.Join(dir, link.Path), func(file *os.File) error {
+			return siteIndexTemplate.Execute(file, index)
+		}); err != nil {
+			return 0, err
+		}
+
+		links = append(links, link)
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].Name < links[j].Name
+	})
+
+	index := &siteIndex{Title: siteTitle(posts), Sections: siteSections(posts), Flairs: links}
+
+	err = writeSiteFile(filepath.Join(dir, "index.html"), func(file *os.File) error {
+		return siteIndexTemplate.Execute(file, index)
+	})
+
+	return len(threads), err
+}
+
+// siteTitle the title of a site: the subreddits its posts belong to, in alphabetical order
+func siteTitle(posts []Post) string {
+
+	seen := make(map[string]bool)
+	subreddits := make([]string, 0)
+
+	for _, post := range posts {
+
+		key := strings.ToLower(post.Subreddit)
+
+		if post.Subreddit != "" && !seen[key] {
+			seen[key] = true
+			subreddits = append(subreddits, "r/"+post.Subreddit)
+		}
+	}
+
+	if len(subreddits) == 0 {
+		return "Archive"
+	}
+
+	sort.Slice(subreddits, func(i, j int) bool {
+		return strings.ToLower(subreddits[i]) < strings.ToLower(subreddits[j])
+	})
+
+	return strings.Join(subreddits, ", ")
+}
+
+// siteThreads the latest snapshot of every post in a store, each with the latest snapshots of its comments
+func siteThreads(store Store) ([]*Thread, error) {
+
+	latest := make(map[string]Snapshot)
+
+	err := store.Snapshots(func(snapshot Snapshot) error {
+
+		if current, ok := latest[snapshot.Fullname]; !ok || snapshot.SavedAt.After(current.SavedAt) {
+			latest[snapshot.Fullname] = snapshot
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make(map[string]*Thread)
+	comments := make(map[string][]Comment)
+
+	for _, snapshot := range latest {
+
+		if snapshot.Post != nil {
+			threads[snapshot.Post.Fullname()] = &Thread{Post: *snapshot.Post, FetchedAt: snapshot.SavedAt}
+		} else if snapshot.Comment != nil {
+			comments[snapshot.Comment.PostID] = append(comments[snapshot.Comment.PostID], *snapshot.Comment)
+		}
+	}
+
+	list := make([]*Thread, 0, len(threads))
+
+	for fullname, thread := range threads {
+
+		postComments := comments[fullname]
+
+		sort.SliceStable(postComments, func(i, j int) bool {
+			return postComments[i].CreatedUTC < postComments[j].CreatedUTC
+		})
+
+		thread.Comments = NewCommentTree(postComments)
+
+		list = append(list, thread)
+	}
+
+	return list, nil
+}
+
+// siteSections group posts by the month they were created, newest first
+func siteSections(posts []Post) []siteSection {
+
+	sorted := append([]Post(nil), posts...)
+
+	sortPosts(sorted)
+
+	sections := make([]siteSection, 0)
+
+	for _, post := range sorted {
+
+		heading := "Unknown date"
+
+		if !post.CreatedOn.IsZero() {
+			heading = post.CreatedOn.Format("January 2006")
+		}
+
+		if len(sections) == 0 || sections[len(sections)-1].Heading != heading {
+			sections = append(sections, siteSection{Heading: heading})
+		}
+
+		sections[len(sections)-1].Posts = append(sections[len(sections)-1].Posts, post)
+	}
+
+	return sections
+}
+
+// siteThreadPage the file name of a thread's page. Post IDs that do not look like reddit's, such as those of items imported from untrusted files, are hashed so they cannot name a path outside the site
+func siteThreadPage(id string) string {
+
+	if ok, _ := regexp.MatchString(apiIDRegex, "t3_"+id); ok {
+		return id + ".html"
+	}
+
+	sum := sha256.Sum256([]byte(id))
+
+	return "post-" + hex.EncodeToString(sum[:8]) + ".html"
+}
+
+// siteSlug a file name safe version of a flair. A hash of the flair keeps flairs that differ only in punctuation apart
+func siteSlug(name string) string {
+
+	slug := strings.Map(func(r rune) rune {
+
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+
+		return '-'
+	}, name)
+
+	hash := sha256.Sum256([]byte(name))
+
+	return strings.Trim(slug, "-") + "-" + hex.EncodeToString(hash[:4])
+}
+
+func writeSiteFile(path string, fn func(file *os.File) error) error {
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := fn(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
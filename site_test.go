@@ -0,0 +1,45 @@
+package rscraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportSiteKeepsPagesInside(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "rscraper-site")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	store := NewMemoryStore()
+
+	for _, id := range []string{"abc123", "../../escaped"} {
+		store.SavePost(Post{ID: id, Title: id, Subreddit: "golang"})
+	}
+
+	site := filepath.Join(dir, "site")
+
+	if _, err := ExportSite(store, site); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "escaped.html")); err == nil {
+		t.Fatal("Thread page written outside the site")
+	}
+
+	if _, err := os.Stat(filepath.Join(site, "threads", "abc123.html")); err != nil {
+		t.Fatalf("Expected a page named after a valid post ID: %s", err)
+	}
+
+	pages, _ := ioutil.ReadDir(filepath.Join(site, "threads"))
+
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 thread pages, got %d", len(pages))
+	}
+}
@@ -0,0 +1,263 @@
+package rscraper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SQLDialectSQLite SQL as understood by SQLite 3.25 or later
+	SQLDialectSQLite = "sqlite"
+
+	// SQLDialectPostgres SQL as understood by PostgreSQL
+	SQLDialectPostgres = "postgres"
+)
+
+// SQLStore a Store keeping snapshots in a SQL database through database/sql. The caller opens the database with a driver of their choice, such as modernc.org/sqlite or github.com/lib/pq, so this package does not depend on any driver
+type SQLStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// NewSQLStore create a new store in an open database, creating its snapshots table if it does not exist
+func NewSQLStore(db *sql.DB, dialect string) (*SQLStore, error) {
+
+	id := "INTEGER PRIMARY KEY AUTOINCREMENT"
+
+	switch dialect {
+	case SQLDialectSQLite:
+	case SQLDialectPostgres:
+		id = "BIGSERIAL PRIMARY KEY"
+	default:
+		return nil, fmt.Errorf("Unsupported SQL dialect '%s'", dialect)
+	}
+
+	store := &SQLStore{db: db, dialect: dialect}
+
+	statements := []string{
+		"CREATE TABLE IF NOT EXISTS rscraper_snapshots (id " + id + ", fullname TEXT NOT NULL, saved_at BIGINT NOT NULL, created_at BIGINT NOT NULL, data TEXT NOT NULL)",
+		"CREATE INDEX IF NOT EXISTS rscraper_snapshots_fullname ON rscraper_snapshots (fullname, saved_at)",
+		"CREATE INDEX IF NOT EXISTS rscraper_snapshots_created_at ON rscraper_snapshots (created_at)",
+	}
+
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// SavePost save a snapshot of a post
+func (me *SQLStore) SavePost(post Post) error {
+
+	return me.SaveSnapshot(Snapshot{Fullname: post.Fullname(), SavedAt: now(), Post: &post})
+}
+
+// SaveComment save a snapshot of a comment
+func (me *SQLStore) SaveComment(comment Comment) error {
+
+	return me.SaveSnapshot(Snapshot{Fullname: comment.Fullname(), SavedAt: now(), Comment: &comment})
+}
+
+// SaveSnapshot insert a snapshot as a new row
+func (me *SQLStore) SaveSnapshot(snapshot Snapshot) error {
+
+	data, err := json.Marshal(snapshot)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = me.db.Exec(me.bind("INSERT INTO rscraper_snapshots (fullname, saved_at, created_at, data) VALUES (?, ?, ?, ?)"),
+		snapshot.Fullname, snapshot.SavedAt.UnixNano(), sqlTime(snapshot.CreatedOn()), string(data))
+
+	return err
+}
+
+// Snapshots call fn with every snapshot in the store, in the order they were saved
+func (me *SQLStore) Snapshots(fn func(snapshot Snapshot) error) error {
+
+	rows, err := me.db.Query("SELECT data FROM rscraper_snapshots ORDER BY id")
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var data string
+
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+
+		var snapshot Snapshot
+
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			return err
+		}
+
+		if err := fn(snapshot); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Query find the latest snapshot of every item matching a query, newest item first. The creation time range and the latest snapshot of each item are selected by the database using its indexes; subreddit, author and link flair are only kept in each row's data, so they are matched as the rows are read
+func (me *SQLStore) Query(q Query) ([]Snapshot, error) {
+
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if !q.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, q.Since.Unix())
+	}
+
+	if !q.Until.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, q.Until.Unix())
+	}
+
+	where := ""
+
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `SELECT data FROM (
+		SELECT id, created_at, data, ROW_NUMBER() OVER (PARTITION BY fullname ORDER BY saved_at DESC, id DESC) AS position FROM rscraper_snapshots` + where + `
+	) latest WHERE position = 1 ORDER BY created_at DESC, id`
+
+	filtered := q.Subreddit != "" || q.Author != "" || q.LinkFlair != ""
+
+	if q.Limit > 0 && !filtered {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := me.db.Query(me.bind(query), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	results := make([]Snapshot, 0)
+
+	for rows.Next() {
+
+		var data string
+
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var snapshot Snapshot
+
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			return nil, err
+		}
+
+		if !q.Matches(&snapshot) {
+			continue
+		}
+
+		results = append(results, snapshot)
+
+		if q.Limit > 0 && len(results) >= q.Limit {
+			break
+		}
+	}
+
+	return results, rows.Err()
+}
+
+// Prune delete snapshots according to a retention policy in a single transaction
+func (me *SQLStore) Prune(policy RetentionPolicy) (int, error) {
+
+	tx, err := me.db.Begin()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	deleted := int64(0)
+
+	if policy.MaxAge > 0 {
+
+		result, err := tx.Exec(me.bind("DELETE FROM rscraper_snapshots WHERE created_at < ?"), sqlTime(now().Add(-policy.MaxAge)))
+
+		if err != nil {
+			return 0, err
+		}
+
+		count, _ := result.RowsAffected()
+		deleted += count
+	}
+
+	if policy.MaxSnapshots > 0 {
+
+		result, err := tx.Exec(me.bind(`DELETE FROM rscraper_snapshots WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY fullname ORDER BY saved_at DESC, id DESC) AS position FROM rscraper_snapshots
+			) ranked WHERE position > ?)`), policy.MaxSnapshots)
+
+		if err != nil {
+			return 0, err
+		}
+
+		count, _ := result.RowsAffected()
+		deleted += count
+	}
+
+	return int(deleted), tx.Commit()
+}
+
+// bind rewrite ? placeholders into the dialect's placeholder syntax
+func (me *SQLStore) bind(query string) string {
+
+	if me.dialect != SQLDialectPostgres {
+		return query
+	}
+
+	var result strings.Builder
+
+	n := 0
+
+	for _, r := range query {
+
+		if r != '?' {
+			result.WriteRune(r)
+			continue
+		}
+
+		n++
+		result.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return result.String()
+}
+
+// sqlTime a time as Unix seconds, with the zero time stored as zero so items without a creation time are the first pruned by age, as in the other stores
+func sqlTime(t time.Time) int64 {
+
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,199 @@
+package rscraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// SubredditStatusActive the subreddit is publicly accessible
+	SubredditStatusActive = "active"
+
+	// SubredditStatusPrivate the subreddit has been made private by its moderators
+	SubredditStatusPrivate = "private"
+
+	// SubredditStatusBanned the subreddit has been banned by reddit
+	SubredditStatusBanned = "banned"
+
+	// SubredditStatusQuarantined the subreddit has been quarantined by reddit
+	SubredditStatusQuarantined = "quarantined"
+
+	// SubredditStatusNotFound the subreddit does not exist
+	SubredditStatusNotFound = "not_found"
+)
+
+// ResponseError an unsuccessful HTTP response returned by the reddit API
+type ResponseError struct {
+	StatusCode int
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+}
+
+func (me *ResponseError) Error() string {
+
+	if me.Reason != "" {
+		return fmt.Sprintf("Reddit API returned %d (%s)", me.StatusCode, me.Reason)
+	}
+
+	return fmt.Sprintf("Reddit API returned %d", me.StatusCode)
+}
+
+func newResponseError(statusCode int, body []byte) *ResponseError {
+
+	var result ResponseError
+
+	json.Unmarshal(body, &result)
+
+	result.StatusCode = statusCode
+
+	return &result
+}
+
+// SubredditStatusError returned when a subreddit exists but cannot be accessed, or does not exist
+type SubredditStatusError struct {
+	Subreddit string
+	Status    string
+}
+
+func (me *SubredditStatusError) Error() string {
+
+	return fmt.Sprintf("Subreddit '%s' is not accessible (%s)", me.Subreddit, me.Status)
+}
+
+// GetSubredditStatus retrieve whether a subreddit is active, private, banned, quarantined or does not exist. Subreddits the guardrails refuse are reported by what was refused: private for private subreddits, and active for NSFW subreddits, which are accessible
+func GetSubredditStatus(subreddit string) (string, error) {
+
+	_, err := GetSubreddit(subreddit)
+
+	return subredditStatus(err)
+}
+
+// WatchSubredditStatus check the status of each subreddit at an interval until the context is cancelled, emitting a change with Field SubredditFieldStatus whenever a subreddit's status differs from its previous check, such as an active subreddit being banned or made private. The first check of each subreddit only records its status. Errors do not stop the watch; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise
+func WatchSubredditStatus(ctx context.Context, interval time.Duration, subreddits ...string) (<-chan SubredditChange, <-chan error) {
+
+	changes := make(chan SubredditChange)
+	errs := make(chan error, 1)
+
+	interval = politeInterval(interval, subreddits...)
+
+	go func() {
+
+		defer close(changes)
+		defer close(errs)
+
+		previous := make(map[string]string)
+
+		for {
+			for _, subreddit := range subreddits {
+
+				status, err := GetSubredditStatus(subreddit)
+
+				if err != nil {
+					sendError(errs, err)
+					continue
+				}
+
+				if change := statusChange(previous, subreddit, status, nil); change != nil {
+					select {
+					case changes <- *change:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes, errs
+}
+
+// subredditStatus the status of a subreddit given the error retrieving it. Errors that say nothing about the subreddit's status are returned
+func subredditStatus(err error) (string, error) {
+
+	switch value := err.(type) {
+	case nil:
+		return SubredditStatusActive, nil
+	case *SubredditStatusError:
+		return value.Status, nil
+	case *GuardrailError:
+
+		if value.Rule == GuardrailPrivate {
+			return SubredditStatusPrivate, nil
+		}
+
+		return SubredditStatusActive, nil
+	}
+
+	return "", err
+}
+
+// statusChange record a subreddit's latest status, returning the change from its previous status if there was one. snapshot is the latest snapshot of the subreddit, if any
+func statusChange(previous map[string]string, subreddit, status string, snapshot *SubredditSnapshot) *SubredditChange {
+
+	key := strings.ToLower(subreddit)
+
+	old, seen := previous[key]
+
+	previous[key] = status
+
+	if !seen || old == status {
+		return nil
+	}
+
+	change := &SubredditChange{Subreddit: subreddit, Field: SubredditFieldStatus, Old: old, New: status}
+
+	if snapshot != nil {
+		change.Subreddit = snapshot.Subreddit.Name
+		change.Snapshot = *snapshot
+	}
+
+	return change
+}
+
+// retryable whether a failed request is worth retrying: reddit rate limited it or failed with a server error, or it never got a response. Other errors, such as a missing or forbidden item, fail the same way every time
+func retryable(err error) bool {
+
+	switch value := err.(type) {
+	case *ResponseError:
+		return value.StatusCode == http.StatusTooManyRequests || value.StatusCode >= 500
+	case *url.Error:
+		return value.Err != context.Canceled && value.Err != context.DeadlineExceeded
+	}
+
+	return false
+}
+
+func subredditError(subreddit string, err error) error {
+
+	responseErr, ok := err.(*ResponseError)
+
+	if !ok {
+		return err
+	}
+
+	switch responseErr.Reason {
+	case SubredditStatusPrivate:
+		return &SubredditStatusError{Subreddit: subreddit, Status: SubredditStatusPrivate}
+	case SubredditStatusBanned:
+		return &SubredditStatusError{Subreddit: subreddit, Status: SubredditStatusBanned}
+	case SubredditStatusQuarantined, "quarantine":
+		return &SubredditStatusError{Subreddit: subreddit, Status: SubredditStatusQuarantined}
+	}
+
+	if responseErr.StatusCode == http.StatusNotFound {
+		return &SubredditStatusError{Subreddit: subreddit, Status: SubredditStatusNotFound}
+	}
+
+	return err
+}
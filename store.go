@@ -0,0 +1,278 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Snapshot a post or comment as it was when it was saved to a store. Exactly one of Post or Comment is set
+type Snapshot struct {
+	Fullname string    `json:"fullname"`
+	SavedAt  time.Time `json:"saved_at"`
+	Post     *Post     `json:"post,omitempty"`
+	Comment  *Comment  `json:"comment,omitempty"`
+
+	// sequence the order the snapshot was saved to a MemoryStore in
+	sequence uint64
+}
+
+// Store persists scraped posts and comments. Every save of an item is kept as a separate snapshot
+type Store interface {
+	SavePost(post Post) error
+	SaveComment(comment Comment) error
+
+	// SaveSnapshot save a snapshot as is, preserving when it was originally saved
+	SaveSnapshot(snapshot Snapshot) error
+
+	// Snapshots call fn with every snapshot in the store, stopping at the first error
+	Snapshots(fn func(snapshot Snapshot) error) error
+
+	// Prune delete snapshots according to a retention policy, returning the number of snapshots deleted
+	Prune(policy RetentionPolicy) (int, error)
+}
+
+// RetentionPolicy which snapshots a store keeps when pruned
+type RetentionPolicy struct {
+
+	// MaxAge delete every snapshot of items created longer ago than this. Zero keeps items of any age
+	MaxAge time.Duration
+
+	// MaxSnapshots keep only this many of the most recent snapshots of each item. Zero keeps every snapshot
+	MaxSnapshots int
+}
+
+// SchedulePrune prune a store with the provided policy at a regular interval until the context is cancelled. Errors do not stop pruning; they are delivered on the returned channel if the consumer is receiving from it and dropped otherwise
+func SchedulePrune(ctx context.Context, store Store, policy RetentionPolicy, interval time.Duration) <-chan error {
+
+	errs := make(chan error, 1)
+
+	go func() {
+
+		defer close(errs)
+
+		for {
+			if _, err := store.Prune(policy); err != nil {
+				sendError(errs, err)
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return errs
+}
+
+// MemoryStore an in-memory Store
+type MemoryStore struct {
+	mutex     sync.RWMutex
+	snapshots map[string][]Snapshot
+	saved     uint64
+}
+
+// NewMemoryStore create a new empty in-memory store
+func NewMemoryStore() *MemoryStore {
+
+	return &MemoryStore{snapshots: make(map[string][]Snapshot)}
+}
+
+// SavePost save a snapshot of a post
+func (me *MemoryStore) SavePost(post Post) error {
+
+	me.save(Snapshot{Fullname: post.Fullname(), SavedAt: now(), Post: &post})
+
+	return nil
+}
+
+// SaveComment save a snapshot of a comment
+func (me *MemoryStore) SaveComment(comment Comment) error {
+
+	me.save(Snapshot{Fullname: comment.Fullname(), SavedAt: now(), Comment: &comment})
+
+	return nil
+}
+
+// SaveSnapshot save a snapshot as is
+func (me *MemoryStore) SaveSnapshot(snapshot Snapshot) error {
+
+	me.save(snapshot)
+
+	return nil
+}
+
+// Snapshots call fn with every snapshot in the store, in the order they were saved
+func (me *MemoryStore) Snapshots(fn func(snapshot Snapshot) error) error {
+
+	me.mutex.RLock()
+
+	snapshots := make([]Snapshot, 0, len(me.snapshots))
+
+	for _, itemSnapshots := range me.snapshots {
+		snapshots = append(snapshots, itemSnapshots...)
+	}
+
+	me.mutex.RUnlock()
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].sequence < snapshots[j].sequence
+	})
+
+	for _, snapshot := range snapshots {
+
+		snapshot.sequence = 0
+
+		if err := fn(snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Prune delete snapshots according to a retention policy
+func (me *MemoryStore) Prune(policy RetentionPolicy) (int, error) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	deleted := 0
+
+	current := now()
+
+	for fullname, snapshots := range me.snapshots {
+
+		kept := pruneSnapshots(snapshots, policy, current)
+
+		deleted += len(snapshots) - len(kept)
+
+		if len(kept) == 0 {
+			delete(me.snapshots, fullname)
+		} else {
+			me.snapshots[fullname] = kept
+		}
+	}
+
+	return deleted, nil
+}
+
+func (me *MemoryStore) save(snapshot Snapshot) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.saved++
+	snapshot.sequence = me.saved
+
+	me.snapshots[snapshot.Fullname] = append(me.snapshots[snapshot.Fullname], snapshot)
+}
+
+// pruneSnapshots the snapshots of a single item, oldest first, that a retention policy keeps
+func pruneSnapshots(snapshots []Snapshot, policy RetentionPolicy, current time.Time) []Snapshot {
+
+	if len(snapshots) == 0 {
+		return snapshots
+	}
+
+	if policy.MaxAge > 0 && current.Sub(snapshots[len(snapshots)-1].CreatedOn()) > policy.MaxAge {
+		return snapshots[:0]
+	}
+
+	if policy.MaxSnapshots > 0 && len(snapshots) > policy.MaxSnapshots {
+
+		sort.SliceStable(snapshots, func(i, j int) bool {
+			return snapshots[i].SavedAt.Before(snapshots[j].SavedAt)
+		})
+
+		return append([]Snapshot(nil), snapshots[len(snapshots)-policy.MaxSnapshots:]...)
+	}
+
+	return snapshots
+}
+
+// CreatedOn when the snapshotted post or comment was created
+func (me *Snapshot) CreatedOn() time.Time {
+
+	if me.Post != nil {
+		return me.Post.CreatedOn
+	}
+
+	if me.Comment != nil {
+		return me.Comment.CreatedOn
+	}
+
+	return time.Time{}
+}
+
+// StoreSink a Sink saving Posts and Comments to a store
+type StoreSink struct {
+	Store Store
+}
+
+// NewStoreSink create a new sink saving to store
+func NewStoreSink(store Store) *StoreSink {
+
+	return &StoreSink{Store: store}
+}
+
+// Write save each Post, Comment or Thread item to the store. Threads are saved as their post and each of their comments
+func (me *StoreSink) Write(items ...interface{}) error {
+
+	for _, item := range items {
+
+		var err error
+
+		switch value := item.(type) {
+		case Post:
+			err = me.Store.SavePost(value)
+		case *Post:
+			err = me.Store.SavePost(*value)
+		case Comment:
+			err = me.Store.SaveComment(value)
+		case *Comment:
+			err = me.Store.SaveComment(*value)
+		case Thread:
+			err = me.saveThread(&value)
+		case *Thread:
+			err = me.saveThread(value)
+		default:
+			err = fmt.Errorf("Cannot store item of type %T", item)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (me *StoreSink) saveThread(thread *Thread) error {
+
+	if err := me.Store.SavePost(thread.Post); err != nil {
+		return err
+	}
+
+	if thread.Comments == nil {
+		return nil
+	}
+
+	for _, comment := range thread.Comments.Flatten() {
+		if err := me.Store.SaveComment(comment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush does nothing, stores save items as they are written
+func (me *StoreSink) Flush() error {
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package rscraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoresKeepSaveOrder(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "rscraper-store")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	store, err := OpenFileStore(filepath.Join(dir, "store.jsonl"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer store.Close()
+
+	ids := []string{"zz", "aa", "mm", "aa", "bb", "zz"}
+
+	for _, id := range ids {
+		if err := store.SavePost(Post{ID: id, Score: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.Prune(RetentionPolicy{MaxSnapshots: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SavePost(Post{ID: "cc"}); err != nil {
+		t.Fatalf("Store not writable after pruning: %s", err)
+	}
+
+	copied := NewMemoryStore()
+
+	if _, err := CopyStore(copied, store); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make([]string, 0)
+
+	copied.Snapshots(func(snapshot Snapshot) error {
+		order = append(order, snapshot.Post.ID)
+		return nil
+	})
+
+	expected := []string{"mm", "aa", "bb", "zz", "cc"}
+
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, order)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package rscraper
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// streamSeenLimit the number of recently emitted item IDs a stream remembers to avoid emitting an item twice
+const streamSeenLimit = 2000
+
+// StreamPosts poll the new listing of one or more subreddits until the context is cancelled, emitting each new post once, oldest first. Fetched posts count against the memory budget until they are emitted, see SetMemoryBudget. Errors do not stop the stream; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise
+func StreamPosts(ctx context.Context, interval time.Duration, subreddits ...string) (<-chan Post, <-chan error) {
+
+	return streamPosts(ctx, interval, "", subreddits)
+}
+
+// StreamPostsFromCursor resume a stream of new posts from a named cursor, see SaveCursor. Posts made since the cursor's post are caught up on first, oldest first, before live polling starts, and the cursor is moved to each post as it is emitted. If the catch up fails, the stream continues live but leaves the cursor at its checkpoint, so the missed posts are caught up on when the stream is resumed again. A cursor that was never saved starts a live stream like StreamPosts
+func StreamPostsFromCursor(ctx context.Context, interval time.Duration, cursor string, subreddits ...string) (<-chan Post, <-chan error) {
+
+	return streamPosts(ctx, interval, cursor, subreddits)
+}
+
+func streamPosts(ctx context.Context, interval time.Duration, cursor string, subreddits []string) (<-chan Post, <-chan error) {
+
+	posts := make(chan Post)
+	errs := make(chan error, 1)
+
+	subreddit := strings.Join(subreddits, "+")
+
+	interval = politeInterval(interval, subreddits...)
+
+	go func() {
+
+		defer close(posts)
+		defer close(errs)
+
+		seen := newSeenSet(streamSeenLimit)
+
+		var hold budgetHold
+
+		defer hold.releaseAll()
+
+		emit := func(post Post) bool {
+
+			defer hold.release(1, postSize(&post))
+
+			if !seen.add(post.ID) {
+				return true
+			}
+
+			select {
+			case posts <- post:
+			case <-ctx.Done():
+				return false
+			}
+
+			if cursor != "" {
+				if err := SaveCursor(cursor, post.Fullname()); err != nil {
+					sendError(errs, err)
+				}
+			}
+
+			return true
+		}
+
+		if cursor != "" {
+
+			checkpoint, err := LoadCursor(cursor)
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			if checkpoint != "" {
+
+				missed, err := CatchUpPosts(ctx, checkpoint, subreddits...)
+
+				if err != nil {
+					sendError(errs, err)
+				}
+
+				if _, capped := err.(*ListingCapError); err != nil && !capped {
+					cursor = ""
+				}
+
+				if hold.acquire(ctx, len(missed), postsSize(missed)) != nil {
+					return
+				}
+
+				for _, post := range missed {
+					if !emit(post) {
+						return
+					}
+				}
+			}
+		}
+
+		for {
+			page, _, err := GetPosts(subreddit, ListingTypeNew, "", "")
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			if hold.acquire(ctx, len(page), postsSize(page)) != nil {
+				return
+			}
+
+			for i := len(page) - 1; i >= 0; i-- {
+				if !emit(page[i]) {
+					return
+				}
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return posts, errs
+}
+
+func sendError(errs chan<- error, err error) {
+
+	select {
+	case errs <- err:
+	default:
+	}
+}
+
+// seenSet a set of IDs that forgets the oldest IDs once it grows beyond its limit
+type seenSet struct {
+	limit int
+	ids   map[string]bool
+	order []string
+}
+
+func newSeenSet(limit int) *seenSet {
+
+	return &seenSet{limit: limit, ids: make(map[string]bool), order: make([]string, 0, limit)}
+}
+
+// add record an ID, returning false if it was already in the set
+func (me *seenSet) add(id string) bool {
+
+	if me.ids[id] {
+		return false
+	}
+
+	me.ids[id] = true
+	me.order = append(me.order, id)
+
+	if len(me.order) > me.limit {
+		delete(me.ids, me.order[0])
+		me.order = me.order[1:]
+	}
+
+	return true
+}
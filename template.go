@@ -0,0 +1,93 @@
+package rscraper
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// templateFuncs helper functions available to item templates
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"truncate": func(length int, text string) string {
+
+		runes := []rune(text)
+
+		if len(runes) <= length {
+			return text
+		}
+
+		return string(runes[:length]) + "..."
+	},
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"permalink": func(permalink string) string {
+		return apiPermalinkHost + permalink
+	},
+}
+
+// TemplateSink renders each item with a Go text/template and writes the result
+type TemplateSink struct {
+	mutex    sync.Mutex
+	template *template.Template
+	writer   *bufio.Writer
+}
+
+// NewTemplateSink create a new sink rendering items with the provided text/template source. Besides the standard template functions, templates can use lower, upper, truncate LENGTH, date LAYOUT and permalink
+func NewTemplateSink(w io.Writer, text string) (*TemplateSink, error) {
+
+	tmpl, err := ParseTemplate(text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateSink{template: tmpl, writer: bufio.NewWriter(w)}, nil
+}
+
+// ParseTemplate parse a text/template for rendering Posts, Comments and other scraped items, with the helper functions used by TemplateSink
+func ParseTemplate(text string) (*template.Template, error) {
+
+	return template.New("item").Funcs(templateFuncs).Parse(text)
+}
+
+// RenderTemplate render a single item with a template parsed by ParseTemplate
+func RenderTemplate(tmpl *template.Template, item interface{}) (string, error) {
+
+	var builder strings.Builder
+
+	if err := tmpl.Execute(&builder, item); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// Write render each item with the sink's template
+func (me *TemplateSink) Write(items ...interface{}) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, item := range items {
+		if err := me.template.Execute(me.writer, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush write any buffered output to the underlying writer
+func (me *TemplateSink) Flush() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.writer.Flush()
+}
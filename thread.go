@@ -0,0 +1,125 @@
+package rscraper
+
+import (
+	"context"
+	"time"
+)
+
+// Thread a post together with its discussion
+type Thread struct {
+	Post      Post         `json:"post"`
+	Comments  *CommentTree `json:"comments"`
+	More      []string     `json:"more"`
+	FetchedAt time.Time    `json:"fetched_at"`
+}
+
+// CommentTree comments arranged by their reply structure
+type CommentTree struct {
+	Roots []*CommentNode `json:"roots"`
+}
+
+// CommentNode a comment in a CommentTree and the replies to it
+type CommentNode struct {
+	Comment Comment        `json:"comment"`
+	Replies []*CommentNode `json:"replies"`
+}
+
+// NewCommentTree arrange a flat list of comments, such as the result of GetComments, by reply structure. Comments whose parent is not in the list become roots. The order of siblings is preserved
+func NewCommentTree(comments []Comment) *CommentTree {
+
+	tree := &CommentTree{Roots: make([]*CommentNode, 0)}
+
+	nodes := make(map[string]*CommentNode)
+
+	for _, comment := range comments {
+
+		comment.Replies = nil
+
+		nodes[comment.Fullname()] = &CommentNode{Comment: comment, Replies: make([]*CommentNode, 0)}
+	}
+
+	for _, comment := range comments {
+
+		node := nodes[comment.Fullname()]
+
+		if parent, ok := nodes[comment.ParentID]; ok && parent != node {
+			parent.Replies = append(parent.Replies, node)
+		} else {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+
+	return tree
+}
+
+// Walk call fn with every comment in the tree, depth first, along with its depth below the roots
+func (me *CommentTree) Walk(fn func(node *CommentNode, depth int)) {
+
+	var walk func(nodes []*CommentNode, depth int)
+
+	walk = func(nodes []*CommentNode, depth int) {
+		for _, node := range nodes {
+			fn(node, depth)
+			walk(node.Replies, depth+1)
+		}
+	}
+
+	walk(me.Roots, 0)
+}
+
+// Flatten the comments in the tree, depth first
+func (me *CommentTree) Flatten() []Comment {
+
+	comments := make([]Comment, 0)
+
+	me.Walk(func(node *CommentNode, depth int) {
+		comments = append(comments, node.Comment)
+	})
+
+	return comments
+}
+
+// Len the number of comments in the tree
+func (me *CommentTree) Len() int {
+
+	count := 0
+
+	me.Walk(func(node *CommentNode, depth int) {
+		count++
+	})
+
+	return count
+}
+
+// GetThread retrieves a post and its comment tree in a single request
+func GetThread(subreddit, postID, after string) (*Thread, error) {
+
+	post, comments, more, err := getThread(context.Background(), getCommentsURL(subreddit, postID, after))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Thread{Post: *post, Comments: NewCommentTree(comments), More: more, FetchedAt: now().UTC()}, nil
+}
+
+// GetThreads retrieves the threads of several posts in a subreddit. Failed requests are retried on their own; posts whose thread could not be retrieved are reported in the error map instead of failing the whole batch
+func GetThreads(subreddit string, postIDs []string) ([]Thread, map[string]error) {
+
+	threads := make([]Thread, 0)
+
+	failures := retryChunks(postIDs, 1, func(chunk []string) error {
+
+		thread, err := GetThread(subreddit, chunk[0], "")
+
+		if err != nil {
+			return err
+		}
+
+		threads = append(threads, *thread)
+
+		return nil
+	})
+
+	return threads, failures
+}
@@ -0,0 +1,58 @@
+package rscraper
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	utcMutex sync.RWMutex
+	utc      bool
+)
+
+type modifiedTimes struct {
+	Edited      interface{} `json:"edited"`
+	BannedAtUTC float64     `json:"banned_at_utc"`
+}
+
+// SetUTC materialize CreatedOn, EditedOn and BannedOn in UTC rather than the local time zone of the machine, so datasets assembled on different machines agree
+func SetUTC(enabled bool) {
+
+	utcMutex.Lock()
+	defer utcMutex.Unlock()
+
+	utc = enabled
+}
+
+// redditTime convert a reddit UTC timestamp into a time, in UTC if SetUTC is enabled and the local time zone otherwise. A zero timestamp converts to the zero time
+func redditTime(timestamp float64) time.Time {
+
+	if timestamp == 0 {
+		return time.Time{}
+	}
+
+	result := time.Unix(int64(timestamp), 0)
+
+	utcMutex.RLock()
+	defer utcMutex.RUnlock()
+
+	if utc {
+		return result.UTC()
+	}
+
+	return result
+}
+
+// extractModifiedTimes when a post or comment was last edited and when it was removed by a moderator. Reddit reports "edited" as false for unedited items and the removal time only to moderators; missing times are zero
+func extractModifiedTimes(object *apiObject) (time.Time, time.Time) {
+
+	var times modifiedTimes
+
+	if err := unmarshal(object.Data, &times); err != nil {
+		return time.Time{}, time.Time{}
+	}
+
+	edited, _ := times.Edited.(float64)
+
+	return redditTime(edited), redditTime(times.BannedAtUTC)
+}
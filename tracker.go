@@ -0,0 +1,242 @@
+package rscraper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SubredditFieldTitle the subreddit's title changed
+	SubredditFieldTitle = "title"
+
+	// SubredditFieldDescription the subreddit's public description changed
+	SubredditFieldDescription = "description"
+
+	// SubredditFieldSidebar the subreddit's sidebar text changed
+	SubredditFieldSidebar = "sidebar"
+
+	// SubredditFieldSubscribers the subreddit's subscriber count changed
+	SubredditFieldSubscribers = "subscribers"
+
+	// SubredditFieldActiveUsers the subreddit's active user count changed
+	SubredditFieldActiveUsers = "active_users"
+
+	// SubredditFieldRule one of the subreddit's rules was added, removed or changed
+	SubredditFieldRule = "rule"
+
+	// SubredditFieldStatus the subreddit's status changed, such as being banned, made private or deleted. Old and New hold SubredditStatus values
+	SubredditFieldStatus = "status"
+)
+
+// SubredditRule a rule of a subreddit
+type SubredditRule struct {
+	ShortName   string `json:"short_name"`
+	Description string `json:"description"`
+	Kind        string `json:"kind"`
+	Priority    int    `json:"priority"`
+}
+
+type subredditRules struct {
+	Rules []SubredditRule `json:"rules"`
+}
+
+// SubredditSnapshot a subreddit's metadata and rules at a point in time
+type SubredditSnapshot struct {
+	Subreddit Subreddit
+	Rules     []SubredditRule
+	TakenAt   time.Time
+}
+
+// SubredditChange a difference between two snapshots of a subreddit. For rule changes Old and New hold the rule's description, and are empty when the rule was added or removed respectively. For status changes they hold the previous and current status
+type SubredditChange struct {
+	Subreddit string
+	Field     string
+	Rule      string
+	Old       string
+	New       string
+	Snapshot  SubredditSnapshot
+}
+
+// GetSubredditRules retrieve the rules of a subreddit
+func GetSubredditRules(subreddit string) ([]SubredditRule, error) {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/r/%s/about/rules.json", subreddit)
+
+	bytes, err := get(redditURL.String())
+
+	if err != nil {
+		return nil, subredditError(subreddit, err)
+	}
+
+	var result subredditRules
+
+	if err = unmarshal(bytes, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Rules, nil
+}
+
+// GetSubredditSnapshot retrieve a subreddit's current metadata and rules
+func GetSubredditSnapshot(subreddit string) (*SubredditSnapshot, error) {
+
+	about, err := GetSubreddit(subreddit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := GetSubredditRules(subreddit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubredditSnapshot{Subreddit: *about, Rules: rules, TakenAt: now()}, nil
+}
+
+// CompareSubreddits compare two snapshots of the same subreddit and return the changes between them
+func CompareSubreddits(old, new *SubredditSnapshot) []SubredditChange {
+
+	changes := make([]SubredditChange, 0)
+
+	if old == nil || new == nil {
+		return changes
+	}
+
+	change := func(field, rule, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, SubredditChange{Subreddit: new.Subreddit.Name, Field: field, Rule: rule, Old: oldValue, New: newValue, Snapshot: *new})
+		}
+	}
+
+	change(SubredditFieldTitle, "", old.Subreddit.Title, new.Subreddit.Title)
+	change(SubredditFieldDescription, "", old.Subreddit.Description, new.Subreddit.Description)
+	change(SubredditFieldSidebar, "", old.Subreddit.Sidebar, new.Subreddit.Sidebar)
+	change(SubredditFieldSubscribers, "", strconv.Itoa(old.Subreddit.Subscribers), strconv.Itoa(new.Subreddit.Subscribers))
+	change(SubredditFieldActiveUsers, "", strconv.Itoa(old.Subreddit.ActiveUsers), strconv.Itoa(new.Subreddit.ActiveUsers))
+
+	oldRules := make(map[string]string)
+
+	for _, rule := range old.Rules {
+		oldRules[rule.ShortName] = rule.Description
+	}
+
+	for _, rule := range new.Rules {
+
+		previous, ok := oldRules[rule.ShortName]
+
+		if !ok {
+			changes = append(changes, SubredditChange{Subreddit: new.Subreddit.Name, Field: SubredditFieldRule, Rule: rule.ShortName, New: rule.Description, Snapshot: *new})
+		} else {
+			change(SubredditFieldRule, rule.ShortName, previous, rule.Description)
+		}
+
+		delete(oldRules, rule.ShortName)
+	}
+
+	for name, description := range oldRules {
+		changes = append(changes, SubredditChange{Subreddit: new.Subreddit.Name, Field: SubredditFieldRule, Rule: name, Old: description, Snapshot: *new})
+	}
+
+	return changes
+}
+
+// SubredditTracker snapshots the metadata of a set of subreddits on a schedule and reports what changed
+type SubredditTracker struct {
+	Subreddits []string
+	Interval   time.Duration
+
+	// OnSnapshot when set, called with every snapshot taken, before its changes are emitted
+	OnSnapshot func(snapshot SubredditSnapshot)
+}
+
+// NewSubredditTracker create a new tracker snapshotting the provided subreddits at an interval
+func NewSubredditTracker(interval time.Duration, subreddits ...string) *SubredditTracker {
+
+	return &SubredditTracker{Subreddits: subreddits, Interval: interval}
+}
+
+// Watch snapshot the subreddits until the context is cancelled, emitting each change between consecutive snapshots. A subreddit that becomes inaccessible, or accessible again, is reported as a SubredditFieldStatus change carrying its last snapshot. Errors do not stop the tracker; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise
+func (me *SubredditTracker) Watch(ctx context.Context) (<-chan SubredditChange, <-chan error) {
+
+	changes := make(chan SubredditChange)
+	errs := make(chan error, 1)
+
+	interval := politeInterval(me.Interval, me.Subreddits...)
+
+	go func() {
+
+		defer close(changes)
+		defer close(errs)
+
+		previous := make(map[string]*SubredditSnapshot)
+		statuses := make(map[string]string)
+
+		for {
+			for _, subreddit := range me.Subreddits {
+
+				key := strings.ToLower(subreddit)
+
+				snapshot, err := GetSubredditSnapshot(subreddit)
+
+				status, statusErr := subredditStatus(err)
+
+				if statusErr != nil {
+					sendError(errs, statusErr)
+					continue
+				}
+
+				last := snapshot
+
+				if last == nil {
+					last = previous[key]
+				}
+
+				if change := statusChange(statuses, subreddit, status, last); change != nil {
+					select {
+					case changes <- *change:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				if snapshot == nil {
+
+					if _, refused := err.(*GuardrailError); refused {
+						sendError(errs, err)
+					}
+
+					continue
+				}
+
+				if me.OnSnapshot != nil {
+					me.OnSnapshot(*snapshot)
+				}
+
+				for _, change := range CompareSubreddits(previous[key], snapshot) {
+					select {
+					case changes <- change:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				previous[key] = snapshot
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes, errs
+}
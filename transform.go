@@ -0,0 +1,653 @@
+package rscraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Transform a compiled transform script, see ParseTransform
+type Transform struct {
+	steps []transformStep
+}
+
+// TransformedItem the fields of an item after a transform, and the route the transform chose for it. It encodes to JSON as its fields alone
+type TransformedItem struct {
+	Route  string
+	Fields map[string]interface{}
+}
+
+type transformStep struct {
+	kind   string
+	fields []string
+	field  string
+	value  transformExpr
+	route  string
+	filter FilterFunc
+}
+
+type transformExpr func(fields map[string]interface{}) interface{}
+
+const (
+	transformStepDrop  = "drop"
+	transformStepSet   = "set"
+	transformStepSkip  = "skip"
+	transformStepRoute = "route"
+)
+
+// ParseTransform compile a transform script. A script is a list of statements, one per line or separated by semicolons outside quoted strings, applied in order to the JSON fields of an item:
+//
+//	drop selftext_html, preview
+//	set title_length = len(title)
+//	set ratio = ups / (ups + downs)
+//	set balance = -downs + ups
+//	skip if score < 10
+//	route "meta" if link_flair_text == "Meta"
+//
+// drop removes fields, set adds or replaces a field with the value of an arithmetic expression over fields and literals, skip discards the item when a filter expression (see ParseFilter) matches, and route assigns the item to a named route when a filter expression matches. Filter expressions can refer to fields added by earlier statements. Expressions referring to missing fields, or dividing by zero, evaluate to null
+func ParseTransform(script string) (*Transform, error) {
+
+	transform := &Transform{steps: make([]transformStep, 0)}
+
+	for i, line := range splitTransformStatements(script) {
+
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		step, err := parseTransformStep(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("Statement %d: %s", i+1, err.Error())
+		}
+
+		transform.steps = append(transform.steps, step)
+	}
+
+	return transform, nil
+}
+
+// splitTransformStatements split a script into statements at line breaks and at semicolons that are not inside a quoted string. Lines starting with # are comments and are left out
+func splitTransformStatements(script string) []string {
+
+	statements := make([]string, 0)
+
+	var current strings.Builder
+
+	quoted, escaped, comment := false, false, false
+
+	for _, c := range script {
+
+		switch {
+		case comment:
+
+			if c == '\n' {
+				comment = false
+			}
+
+			continue
+		case quoted:
+
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				quoted = false
+			case c == '\n':
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '#' && strings.TrimSpace(current.String()) == "":
+			comment = true
+			current.Reset()
+			continue
+		case c == ';':
+			statements = append(statements, current.String())
+			current.Reset()
+			continue
+		}
+
+		if c == '\n' {
+			statements = append(statements, current.String())
+			current.Reset()
+			continue
+		}
+
+		current.WriteRune(c)
+	}
+
+	return append(statements, current.String())
+}
+
+// LoadTransforms read a JSON object mapping transform names to transform scripts and compile each of them
+func LoadTransforms(r io.Reader) (map[string]*Transform, error) {
+
+	scripts := make(map[string]string)
+
+	if err := json.NewDecoder(r).Decode(&scripts); err != nil {
+		return nil, err
+	}
+
+	transforms := make(map[string]*Transform)
+
+	for name, script := range scripts {
+
+		transform, err := ParseTransform(script)
+
+		if err != nil {
+			return nil, fmt.Errorf("Transform '%s': %s", name, err.Error())
+		}
+
+		transforms[name] = transform
+	}
+
+	return transforms, nil
+}
+
+func parseTransformStep(line string) (transformStep, error) {
+
+	keyword := line
+
+	if end := strings.IndexFunc(line, unicode.IsSpace); end >= 0 {
+		keyword = line[:end]
+	}
+
+	rest := strings.TrimSpace(line[len(keyword):])
+
+	switch keyword {
+	case transformStepDrop:
+
+		fields := make([]string, 0)
+
+		for _, field := range strings.Split(rest, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+
+		if len(fields) == 0 {
+			return transformStep{}, fmt.Errorf("Expected fields to drop")
+		}
+
+		return transformStep{kind: transformStepDrop, fields: fields}, nil
+	case transformStepSet:
+
+		equals := strings.Index(rest, "=")
+
+		if equals < 0 {
+			return transformStep{}, fmt.Errorf("Expected 'set FIELD = EXPRESSION'")
+		}
+
+		field := strings.TrimSpace(rest[:equals])
+
+		value, err := parseTransformExpr(rest[equals+1:])
+
+		if err != nil {
+			return transformStep{}, err
+		}
+
+		return transformStep{kind: transformStepSet, field: field, value: value}, nil
+	case transformStepSkip:
+
+		if !strings.HasPrefix(rest, "if ") {
+			return transformStep{}, fmt.Errorf("Expected 'skip if FILTER'")
+		}
+
+		filter, err := ParseFilter(rest[3:])
+
+		if err != nil {
+			return transformStep{}, err
+		}
+
+		return transformStep{kind: transformStepSkip, filter: filter}, nil
+	case transformStepRoute:
+
+		end := quotedStringEnd(rest)
+
+		if end < 0 {
+			return transformStep{}, fmt.Errorf("Route names must be quoted strings")
+		}
+
+		route, err := strconv.Unquote(rest[:end])
+
+		if err != nil {
+			return transformStep{}, fmt.Errorf("Route names must be quoted strings")
+		}
+
+		condition := strings.TrimSpace(rest[end:])
+
+		if !strings.HasPrefix(condition, "if ") {
+			return transformStep{}, fmt.Errorf("Expected 'route \"NAME\" if FILTER'")
+		}
+
+		filter, err := ParseFilter(condition[3:])
+
+		if err != nil {
+			return transformStep{}, err
+		}
+
+		return transformStep{kind: transformStepRoute, route: route, filter: filter}, nil
+	}
+
+	return transformStep{}, fmt.Errorf("Unknown statement '%s'", keyword)
+}
+
+// quotedStringEnd the index just past the quoted string text starts with, or -1 when it does not start with a terminated quoted string
+func quotedStringEnd(text string) int {
+
+	if !strings.HasPrefix(text, "\"") {
+		return -1
+	}
+
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// Apply run the transform on an item, such as a Post or Comment. Returns nil when the item is skipped
+func (me *Transform) Apply(item interface{}) (*TransformedItem, error) {
+
+	fields, err := transformFields(item)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransformedItem{Fields: fields}
+
+	if transformed, ok := item.(*TransformedItem); ok {
+		result.Route = transformed.Route
+	}
+
+	for _, step := range me.steps {
+
+		switch step.kind {
+		case transformStepDrop:
+			for _, field := range step.fields {
+				delete(fields, field)
+			}
+		case transformStepSet:
+			fields[step.field] = step.value(fields)
+		case transformStepSkip:
+			if step.filter(fields) {
+				return nil, nil
+			}
+		case transformStepRoute:
+			if step.filter(fields) {
+				result.Route = step.route
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// MarshalJSON encode the item's fields
+func (me *TransformedItem) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(me.Fields)
+}
+
+// transformFields the JSON fields of an item, copied so the transform does not modify the original
+func transformFields(item interface{}) (map[string]interface{}, error) {
+
+	if transformed, ok := item.(*TransformedItem); ok {
+		item = transformed.Fields
+	}
+
+	data, err := json.Marshal(item)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("Cannot transform item of type %T, it does not encode to a JSON object", item)
+	}
+
+	return fields, nil
+}
+
+// TransformItems apply a transform to every item, passing on the TransformedItems and dropping skipped items and items that cannot be transformed
+func TransformItems(ctx context.Context, in <-chan interface{}, transform *Transform) <-chan interface{} {
+
+	return Map(ctx, in, func(item interface{}) interface{} {
+
+		transformed, err := transform.Apply(item)
+
+		if err != nil || transformed == nil {
+			return nil
+		}
+
+		return transformed
+	})
+}
+
+// Route split TransformedItems by their route into one output per named route, plus a final output for items with any other route and items that are not TransformedItems. Every output must be consumed
+func Route(ctx context.Context, in <-chan interface{}, routes ...string) []<-chan interface{} {
+
+	outs := make([]chan interface{}, len(routes)+1)
+	results := make([]<-chan interface{}, len(outs))
+
+	for i := range outs {
+		outs[i] = make(chan interface{})
+		results[i] = outs[i]
+	}
+
+	index := make(map[string]int, len(routes))
+
+	for i, route := range routes {
+		index[route] = i
+	}
+
+	go func() {
+
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for item, ok := receive(ctx, in); ok; item, ok = receive(ctx, in) {
+
+			out := outs[len(routes)]
+
+			if transformed, ok := item.(*TransformedItem); ok {
+				if i, ok := index[transformed.Route]; ok {
+					out = outs[i]
+				}
+			}
+
+			if !send(ctx, out, item) {
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// transformLexer splits set expressions into tokens
+type transformLexer struct {
+	tokens []string
+	pos    int
+}
+
+func parseTransformExpr(text string) (transformExpr, error) {
+
+	lexer := &transformLexer{tokens: make([]string, 0)}
+
+	for i := 0; i < len(text); {
+
+		c := rune(text[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("+-*/()", c):
+			lexer.tokens = append(lexer.tokens, string(c))
+			i++
+		case c == '"':
+
+			end := i + 1
+
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			if end >= len(text) {
+				return nil, fmt.Errorf("Unterminated string in expression")
+			}
+
+			lexer.tokens = append(lexer.tokens, text[i:end+1])
+			i = end + 1
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.':
+
+			end := i + 1
+
+			for end < len(text) && (unicode.IsLetter(rune(text[end])) || unicode.IsDigit(rune(text[end])) || text[end] == '_' || text[end] == '.') {
+				end++
+			}
+
+			lexer.tokens = append(lexer.tokens, text[i:end])
+			i = end
+		default:
+			return nil, fmt.Errorf("Unexpected character '%c' in expression", c)
+		}
+	}
+
+	expr, err := lexer.parseSum()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lexer.pos < len(lexer.tokens) {
+		return nil, fmt.Errorf("Unexpected '%s' in expression", lexer.tokens[lexer.pos])
+	}
+
+	return expr, nil
+}
+
+func (me *transformLexer) peek() string {
+
+	if me.pos < len(me.tokens) {
+		return me.tokens[me.pos]
+	}
+
+	return ""
+}
+
+func (me *transformLexer) parseSum() (transformExpr, error) {
+
+	left, err := me.parseProduct()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for me.peek() == "+" || me.peek() == "-" {
+
+		operator := me.tokens[me.pos]
+		me.pos++
+
+		right, err := me.parseProduct()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = transformArithmetic(operator, left, right)
+	}
+
+	return left, nil
+}
+
+func (me *transformLexer) parseProduct() (transformExpr, error) {
+
+	left, err := me.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for me.peek() == "*" || me.peek() == "/" {
+
+		operator := me.tokens[me.pos]
+		me.pos++
+
+		right, err := me.parseOperand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = transformArithmetic(operator, left, right)
+	}
+
+	return left, nil
+}
+
+func (me *transformLexer) parseOperand() (transformExpr, error) {
+
+	token := me.peek()
+
+	me.pos++
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("Unexpected end of expression")
+	case token == "(":
+
+		inner, err := me.parseSum()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if me.peek() != ")" {
+			return nil, fmt.Errorf("Missing ')' in expression")
+		}
+
+		me.pos++
+
+		return inner, nil
+	case token == "len" && me.peek() == "(":
+
+		me.pos++
+
+		inner, err := me.parseSum()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if me.peek() != ")" {
+			return nil, fmt.Errorf("Missing ')' after len argument")
+		}
+
+		me.pos++
+
+		return func(fields map[string]interface{}) interface{} {
+
+			switch value := inner(fields).(type) {
+			case string:
+				return float64(len([]rune(value)))
+			case []interface{}:
+				return float64(len(value))
+			case map[string]interface{}:
+				return float64(len(value))
+			}
+
+			return nil
+		}, nil
+	case strings.HasPrefix(token, "\""):
+
+		value, err := strconv.Unquote(token)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid string %s in expression", token)
+		}
+
+		return func(fields map[string]interface{}) interface{} { return value }, nil
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+
+		value, err := strconv.ParseFloat(token, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number '%s' in expression", token)
+		}
+
+		return func(fields map[string]interface{}) interface{} { return value }, nil
+	case token == "-":
+
+		inner, err := me.parseOperand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(fields map[string]interface{}) interface{} {
+
+			if value, ok := inner(fields).(float64); ok {
+				return -value
+			}
+
+			return nil
+		}, nil
+	case token == "true" || token == "false":
+
+		value := token == "true"
+
+		return func(fields map[string]interface{}) interface{} { return value }, nil
+	case strings.ContainsAny(token, "+-*/)"):
+		return nil, fmt.Errorf("Unexpected '%s' in expression", token)
+	}
+
+	return func(fields map[string]interface{}) interface{} { return fields[token] }, nil
+}
+
+// transformArithmetic combine two expressions with an arithmetic operator. + also concatenates strings
+func transformArithmetic(operator string, left, right transformExpr) transformExpr {
+
+	return func(fields map[string]interface{}) interface{} {
+
+		a, b := left(fields), right(fields)
+
+		if operator == "+" {
+			if x, ok := a.(string); ok {
+				if y, ok := b.(string); ok {
+					return x + y
+				}
+			}
+		}
+
+		x, ok := a.(float64)
+
+		if !ok {
+			return nil
+		}
+
+		y, ok := b.(float64)
+
+		if !ok {
+			return nil
+		}
+
+		switch operator {
+		case "+":
+			return x + y
+		case "-":
+			return x - y
+		case "*":
+			return x * y
+		}
+
+		if y == 0 {
+			return nil
+		}
+
+		return x / y
+	}
+}
@@ -0,0 +1,26 @@
+package rscraper
+
+import "testing"
+
+func TestTransformQuotedSemicolonsAndUnaryMinus(t *testing.T) {
+
+	transform, err := ParseTransform("# weights\nset balance = -downs + ups; route \"a;b\" if title == \"x; y\"")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := transform.Apply(map[string]interface{}{"title": "x; y", "ups": 5.0, "downs": 2.0})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.Route != "a;b" {
+		t.Fatalf("Expected route 'a;b', got '%s'", item.Route)
+	}
+
+	if balance, ok := item.Fields["balance"].(float64); !ok || balance != 3 {
+		t.Fatalf("Expected balance 3, got %v", item.Fields["balance"])
+	}
+}
@@ -0,0 +1,162 @@
+package rscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// UserActivity a post or comment made by a user. Exactly one of Post or Comment is set
+type UserActivity struct {
+	Post    *Post
+	Comment *Comment
+}
+
+// ID the ID of the post or comment
+func (me *UserActivity) ID() string {
+
+	if me.Post != nil {
+		return me.Post.Fullname()
+	}
+
+	return me.Comment.Fullname()
+}
+
+// GetUserActivity retrieves a user's most recent posts and comments, newest first
+func GetUserActivity(username, after string) ([]UserActivity, string, error) {
+
+	activity := make([]UserActivity, 0)
+
+	redditURL := getUserOverviewURL(username, after)
+
+	object, err := getResponse(redditURL.String())
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	list, err := extractListing(object)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	after = ""
+
+	if ok, _ := regexp.MatchString(apiIDRegex, list.After); ok {
+		after = list.After
+	}
+
+	provenance := newProvenance(redditURL.String(), 0, false)
+
+	for _, child := range list.Children {
+
+		if post, err := extractPost(&child); err == nil {
+
+			if guardrailsAllowPost(post) {
+				post.Provenance = provenance.copy()
+				activity = append(activity, UserActivity{Post: post})
+			}
+
+			continue
+		}
+
+		comment, err := extractComment(&child)
+
+		if err != nil {
+			return nil, "", errors.New("API Object is not a Post or Comment")
+		}
+
+		if guardrailsAllowComment(comment) {
+			comment.Provenance = provenance.copy()
+			activity = append(activity, UserActivity{Comment: comment})
+		}
+	}
+
+	return activity, after, nil
+}
+
+// StreamUser poll a user's overview until the context is cancelled, emitting each new post or comment once, oldest first. Fetched items count against the memory budget until they are emitted, see SetMemoryBudget. Errors do not stop the stream; they are delivered on the error channel if the consumer is receiving from it and dropped otherwise
+func StreamUser(ctx context.Context, interval time.Duration, username string) (<-chan UserActivity, <-chan error) {
+
+	activity := make(chan UserActivity)
+	errs := make(chan error, 1)
+
+	go func() {
+
+		defer close(activity)
+		defer close(errs)
+
+		seen := newSeenSet(streamSeenLimit)
+
+		var hold budgetHold
+
+		defer hold.releaseAll()
+
+		for {
+			page, _, err := GetUserActivity(username, "")
+
+			if err != nil {
+				sendError(errs, err)
+			}
+
+			var size int64
+
+			for _, item := range page {
+				size += itemSize(item)
+			}
+
+			if hold.acquire(ctx, len(page), size) != nil {
+				return
+			}
+
+			for i := len(page) - 1; i >= 0; i-- {
+
+				size := itemSize(page[i])
+
+				if !seen.add(page[i].ID()) {
+					hold.release(1, size)
+					continue
+				}
+
+				select {
+				case activity <- page[i]:
+				case <-ctx.Done():
+					return
+				}
+
+				hold.release(1, size)
+			}
+
+			select {
+			case <-after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return activity, errs
+}
+
+func getUserOverviewURL(username, after string) *url.URL {
+
+	redditURL := getBaseURL()
+
+	redditURL.Path = fmt.Sprintf("/user/%s/overview.json", username)
+
+	q := redditURL.Query()
+
+	q.Set("sort", ListingTypeNew)
+
+	if ok, _ := regexp.MatchString(apiIDRegex, after); ok {
+		q.Set("after", after)
+	}
+
+	redditURL.RawQuery = q.Encode()
+
+	return redditURL
+}
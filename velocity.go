@@ -0,0 +1,80 @@
+package rscraper
+
+import (
+	"net/url"
+	"time"
+)
+
+// ThreadVelocity the comment activity of a thread over a sliding window
+type ThreadVelocity struct {
+	Window            time.Duration
+	Comments          int
+	CommentsPerMinute float64
+	UniqueCommenters  int
+}
+
+// velocityTracker the comments of a watched thread within its sliding window. Comments hidden behind "more" links are only counted once they have been returned
+type velocityTracker struct {
+	velocity ThreadVelocity
+	comments map[string]Comment
+	crossed  bool
+}
+
+func newVelocityTracker() *velocityTracker {
+
+	return &velocityTracker{comments: make(map[string]Comment)}
+}
+
+// velocityThreadURL a thread with its newest comments first, so comments made since the last fetch are counted even on threads too large to be returned whole
+func velocityThreadURL(subreddit, postID string) *url.URL {
+
+	redditURL := getCommentsURL(subreddit, postID, "")
+
+	q := redditURL.Query()
+
+	q.Set("sort", "new")
+
+	redditURL.RawQuery = q.Encode()
+
+	return redditURL
+}
+
+// update record the comments of a fetch and recompute the velocity over window, reporting whether it has just crossed the thresholds. Thresholds left at zero are ignored
+func (me *velocityTracker) update(comments []Comment, window time.Duration, minCommentsPerMinute float64, minUniqueCommenters int) (ThreadVelocity, bool) {
+
+	start := now().Add(-window)
+
+	for _, comment := range comments {
+		if createdInstant(comment.CreatedUTC).After(start) {
+			me.comments[comment.ID] = comment
+		}
+	}
+
+	authors := make(map[string]bool)
+
+	for id, comment := range me.comments {
+
+		if !createdInstant(comment.CreatedUTC).After(start) {
+			delete(me.comments, id)
+			continue
+		}
+
+		if comment.Author != "" && comment.Author != apiDeletedAuthor {
+			authors[comment.Author] = true
+		}
+	}
+
+	me.velocity = ThreadVelocity{Window: window, Comments: len(me.comments), UniqueCommenters: len(authors)}
+
+	if minutes := window.Minutes(); minutes > 0 {
+		me.velocity.CommentsPerMinute = float64(len(me.comments)) / minutes
+	}
+
+	crossed := me.velocity.CommentsPerMinute >= minCommentsPerMinute && me.velocity.UniqueCommenters >= minUniqueCommenters
+
+	rising := crossed && !me.crossed
+
+	me.crossed = crossed
+
+	return me.velocity, rising
+}
@@ -0,0 +1,272 @@
+package rscraper
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WARCWriter records raw HTTP request and response pairs as WARC 1.1 records, readable by Internet Archive tooling such as pywb and warcio
+type WARCWriter struct {
+
+	// Compress write each record as a separate gzip member, as expected of .warc.gz files
+	Compress bool
+
+	// OnError when set, called with every error recording a request made by the package. Recording errors never fail the request itself
+	OnError func(err error)
+
+	mutex  sync.Mutex
+	writer io.Writer
+	err    error
+}
+
+var (
+	warcMutex    sync.RWMutex
+	warcRecorder *WARCWriter
+)
+
+// NewWARCWriter create a new WARC writer. A warcinfo record describing the crawler is written first
+func NewWARCWriter(w io.Writer, compress bool) (*WARCWriter, error) {
+
+	writer := &WARCWriter{Compress: compress, writer: w}
+
+	info := []byte("software: " + apiUserAgent + "\r\nformat: WARC File Format 1.1\r\n")
+
+	if err := writer.writeRecord("warcinfo", "", "application/warc-fields", "", info); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// SetWARCRecorder record every request made by the package, and the response to it, to a WARC writer. Passing nil stops recording
+func SetWARCRecorder(w *WARCWriter) {
+
+	warcMutex.Lock()
+	defer warcMutex.Unlock()
+
+	warcRecorder = w
+}
+
+// Err the first error recording a request made by the package, if any. Requests whose recording failed are missing from the archive
+func (me *WARCWriter) Err() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.err
+}
+
+// fail report an error recording a request, without failing the request
+func (me *WARCWriter) fail(err error) {
+
+	me.mutex.Lock()
+
+	if me.err == nil {
+		me.err = err
+	}
+
+	me.mutex.Unlock()
+
+	if me.OnError != nil {
+		me.OnError(err)
+	}
+}
+
+func currentWARCRecorder() *WARCWriter {
+
+	warcMutex.RLock()
+	defer warcMutex.RUnlock()
+
+	return warcRecorder
+}
+
+// recordWARC record a request and response to the current WARC recorder, if any. The raw request must be captured before the request is sent. Failures are reported through the recorder rather than to the caller
+func recordWARC(rawRequest []byte, resp *http.Response, body []byte) {
+
+	recorder := currentWARCRecorder()
+
+	if recorder == nil || rawRequest == nil {
+		return
+	}
+
+	if err := recorder.Record(RedactURL(resp.Request.URL.String()), rawRequest, rawResponse(resp, body)); err != nil {
+		recorder.fail(fmt.Errorf("Unable to record %s: %s", RedactURL(resp.Request.URL.String()), err.Error()))
+	}
+}
+
+// dumpWARCRequest the raw bytes of a request about to be sent, when a WARC recorder is set. The Authorization and Proxy-Authorization headers are left out, and redacted parameters are scrubbed from the request target and form bodies, so credentials do not end up in archives. Returns nil, reporting the failure through the recorder, when the request cannot be captured
+func dumpWARCRequest(req *http.Request) []byte {
+
+	recorder := currentWARCRecorder()
+
+	if recorder == nil {
+		return nil
+	}
+
+	raw, err := httputil.DumpRequestOut(req, true)
+
+	if err != nil {
+		recorder.fail(fmt.Errorf("Unable to record %s: %s", RedactURL(req.URL.String()), err.Error()))
+		return nil
+	}
+
+	end := bytes.Index(raw, []byte("\r\n\r\n"))
+
+	if end < 0 {
+		return raw
+	}
+
+	body := raw[end+4:]
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil && redactValues(form) {
+			body = []byte(form.Encode())
+		}
+	}
+
+	lines := bytes.Split(raw[:end], []byte("\r\n"))
+	kept := make([][]byte, 0, len(lines))
+
+	for i, line := range lines {
+
+		lower := bytes.ToLower(line)
+
+		switch {
+		case i == 0:
+			line = redactRequestLine(line)
+		case bytes.HasPrefix(lower, []byte("authorization:")), bytes.HasPrefix(lower, []byte("proxy-authorization:")):
+			continue
+		case bytes.HasPrefix(lower, []byte("content-length:")):
+			line = []byte("Content-Length: " + strconv.Itoa(len(body)))
+		}
+
+		kept = append(kept, line)
+	}
+
+	dump := append(bytes.Join(kept, []byte("\r\n")), "\r\n\r\n"...)
+
+	return append(dump, body...)
+}
+
+// redactRequestLine scrub the request target of an HTTP request line, such as GET /path?query HTTP/1.1
+func redactRequestLine(line []byte) []byte {
+
+	parts := strings.SplitN(string(line), " ", 3)
+
+	if len(parts) != 3 {
+		return line
+	}
+
+	parts[1] = RedactURL(parts[1])
+
+	return []byte(strings.Join(parts, " "))
+}
+
+// Record write a request record and the response record it is concurrent with
+func (me *WARCWriter) Record(targetURI string, rawRequest, rawResponse []byte) error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	responseID := newWARCRecordID()
+
+	if err := me.writeRecord("response", targetURI, "application/http;msgtype=response", responseID, rawResponse); err != nil {
+		return err
+	}
+
+	return me.writeRecord("request", targetURI, "application/http;msgtype=request", "", rawRequest, "WARC-Concurrent-To: "+responseID)
+}
+
+func (me *WARCWriter) writeRecord(recordType, targetURI, contentType, recordID string, block []byte, headers ...string) error {
+
+	if recordID == "" {
+		recordID = newWARCRecordID()
+	}
+
+	digest := sha1.Sum(block)
+
+	var record bytes.Buffer
+
+	record.WriteString("WARC/1.1\r\n")
+	record.WriteString("WARC-Type: " + recordType + "\r\n")
+	record.WriteString("WARC-Record-ID: " + recordID + "\r\n")
+	record.WriteString("WARC-Date: " + now().UTC().Format(time.RFC3339) + "\r\n")
+
+	if targetURI != "" {
+		record.WriteString("WARC-Target-URI: " + targetURI + "\r\n")
+	}
+
+	for _, header := range headers {
+		record.WriteString(header + "\r\n")
+	}
+
+	record.WriteString("WARC-Block-Digest: sha1:" + base32.StdEncoding.EncodeToString(digest[:]) + "\r\n")
+	record.WriteString("Content-Type: " + contentType + "\r\n")
+	record.WriteString("Content-Length: " + strconv.Itoa(len(block)) + "\r\n\r\n")
+	record.Write(block)
+	record.WriteString("\r\n\r\n")
+
+	if !me.Compress {
+		_, err := me.writer.Write(record.Bytes())
+		return err
+	}
+
+	compressed := gzip.NewWriter(me.writer)
+
+	if _, err := compressed.Write(record.Bytes()); err != nil {
+		return err
+	}
+
+	return compressed.Close()
+}
+
+// rawResponse rebuild the raw bytes of a response. The body has already been read and, if the transport decompressed it, its length and encoding headers no longer apply. Values of redacted parameters are scrubbed from the body, so tokens returned by reddit do not end up in archives
+func rawResponse(resp *http.Response, body []byte) []byte {
+
+	body = redactJSON(body)
+
+	var raw bytes.Buffer
+
+	writer := bufio.NewWriter(&raw)
+
+	fmt.Fprintf(writer, "HTTP/%d.%d %s\r\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+
+	header := resp.Header.Clone()
+
+	header.Del("Content-Encoding")
+	header.Del("Transfer-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	header.Write(writer)
+
+	writer.WriteString("\r\n")
+	writer.Write(body)
+	writer.Flush()
+
+	return raw.Bytes()
+}
+
+func newWARCRecordID() string {
+
+	id := make([]byte, 16)
+
+	rand.Read(id)
+
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
@@ -0,0 +1,104 @@
+package rscraper
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct {
+	fail bool
+	buf  bytes.Buffer
+}
+
+func (me *failingWriter) Write(p []byte) (int, error) {
+
+	if me.fail {
+		return 0, errors.New("Disk full")
+	}
+
+	return me.buf.Write(p)
+}
+
+func TestWARCFailureDoesNotFailRequest(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	writer := &failingWriter{}
+
+	recorder, err := NewWARCWriter(writer, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reported := 0
+
+	recorder.OnError = func(err error) {
+		reported++
+	}
+
+	SetWARCRecorder(recorder)
+	defer SetWARCRecorder(nil)
+
+	if _, err := post(server.URL, url.Values{"password": {"hunter2"}, "user": {"someone"}}); err != nil {
+		t.Fatalf("Request failed: %s", err)
+	}
+
+	if recorder.Err() != nil {
+		t.Fatalf("Unexpected recording error: %s", recorder.Err())
+	}
+
+	archive := writer.buf.String()
+
+	if strings.Contains(archive, "hunter2") || !strings.Contains(archive, "password="+redactedPlaceholder) {
+		t.Fatalf("Form body not redacted:\n%s", archive)
+	}
+
+	writer.fail = true
+
+	if _, err := get(server.URL); err != nil {
+		t.Fatalf("Request failed when recording failed: %s", err)
+	}
+
+	if reported != 1 || recorder.Err() == nil {
+		t.Fatalf("Expected the recording error to be reported once, got %d reports and %v", reported, recorder.Err())
+	}
+}
+
+func TestWARCRedactsTokenResponses(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "abc123secret", "token_type": "bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	writer := &failingWriter{}
+
+	recorder, err := NewWARCWriter(writer, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetWARCRecorder(recorder)
+	defer SetWARCRecorder(nil)
+
+	if _, err := get(server.URL); err != nil {
+		t.Fatalf("Request failed: %s", err)
+	}
+
+	archive := writer.buf.String()
+
+	if strings.Contains(archive, "abc123secret") || !strings.Contains(archive, `"access_token": "`+redactedPlaceholder+`"`) {
+		t.Fatalf("Token response not redacted:\n%s", archive)
+	}
+}
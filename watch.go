@@ -0,0 +1,224 @@
+package rscraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const apiMaxIDsPerRequest = 100
+
+// Watchlist a set of posts that are periodically re-fetched to detect changes
+type Watchlist struct {
+	Interval time.Duration
+
+	// Window the sliding window comment velocity is measured over. If positive, the comments of every watched post are re-fetched along with it and a velocity event is emitted each time a post's velocity crosses the thresholds
+	Window time.Duration
+
+	// MinCommentsPerMinute and MinUniqueCommenters thresholds for velocity events. Thresholds left at zero are ignored; an event is emitted each time the velocity goes from below to meeting all the others
+	MinCommentsPerMinute float64
+	MinUniqueCommenters  int
+
+	mutex      sync.Mutex
+	posts      map[string]*Post
+	velocities map[string]*velocityTracker
+}
+
+// NewWatchlist create a new empty watchlist that re-fetches its posts at the provided interval
+func NewWatchlist(interval time.Duration) *Watchlist {
+
+	return &Watchlist{Interval: interval, posts: make(map[string]*Post), velocities: make(map[string]*velocityTracker)}
+}
+
+// Velocity a watched post's comment velocity as of its most recent fetch
+func (me *Watchlist) Velocity(postID string) ThreadVelocity {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if tracker, ok := me.velocities[postFullname(postID)]; ok {
+		return tracker.velocity
+	}
+
+	return ThreadVelocity{Window: me.Window}
+}
+
+// Add start watching a post
+func (me *Watchlist) Add(postID string) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	fullname := postFullname(postID)
+
+	if _, ok := me.posts[fullname]; !ok {
+		me.posts[fullname] = nil
+	}
+}
+
+// Remove stop watching a post
+func (me *Watchlist) Remove(postID string) {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	delete(me.posts, postFullname(postID))
+	delete(me.velocities, postFullname(postID))
+}
+
+// Watch re-fetch all watched posts until the context is cancelled, emitting an event for each detected change
+func (me *Watchlist) Watch(ctx context.Context) <-chan PostEvent {
+
+	events := make(chan PostEvent)
+
+	go func() {
+
+		defer close(events)
+
+		for {
+			for _, event := range me.poll(ctx) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-after(me.Interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+func (me *Watchlist) poll(ctx context.Context) []PostEvent {
+
+	events := make([]PostEvent, 0)
+
+	fullnames := me.fullnames()
+
+	for start := 0; start < len(fullnames); start += apiMaxIDsPerRequest {
+
+		end := start + apiMaxIDsPerRequest
+
+		if end > len(fullnames) {
+			end = len(fullnames)
+		}
+
+		posts, err := getPostsByID(fullnames[start:end])
+
+		if err != nil {
+			events = append(events, PostEvent{Type: EventTypeError, Err: err})
+			continue
+		}
+
+		me.mutex.Lock()
+
+		watched := make([]*Post, 0, len(posts))
+
+		for i := range posts {
+
+			fullname := postFullname(posts[i].ID)
+
+			old, ok := me.posts[fullname]
+
+			if !ok {
+				continue
+			}
+
+			events = append(events, ComparePosts(old, &posts[i])...)
+			me.posts[fullname] = &posts[i]
+
+			watched = append(watched, &posts[i])
+		}
+
+		me.mutex.Unlock()
+
+		if me.Window > 0 {
+			for _, post := range watched {
+				events = append(events, me.pollVelocity(ctx, post)...)
+			}
+		}
+	}
+
+	return events
+}
+
+// pollVelocity re-fetch a post's comments, newest first, and emit a velocity event if its comment velocity has just crossed the thresholds
+func (me *Watchlist) pollVelocity(ctx context.Context, post *Post) []PostEvent {
+
+	_, comments, _, err := getThread(ctx, velocityThreadURL(post.Subreddit, post.ID))
+
+	if err != nil {
+		return []PostEvent{{Type: EventTypeError, PostID: post.ID, Err: err}}
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	fullname := postFullname(post.ID)
+
+	if _, ok := me.posts[fullname]; !ok {
+		return nil
+	}
+
+	tracker, ok := me.velocities[fullname]
+
+	if !ok {
+		tracker = newVelocityTracker()
+		me.velocities[fullname] = tracker
+	}
+
+	if velocity, crossed := tracker.update(comments, me.Window, me.MinCommentsPerMinute, me.MinUniqueCommenters); crossed {
+		return []PostEvent{{Type: EventTypeVelocity, PostID: post.ID, Post: *post, Velocity: &velocity}}
+	}
+
+	return nil
+}
+
+func (me *Watchlist) fullnames() []string {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	fullnames := make([]string, 0, len(me.posts))
+
+	for fullname := range me.posts {
+		fullnames = append(fullnames, fullname)
+	}
+
+	return fullnames
+}
+
+// WatchThread re-fetch a single post until the context is cancelled, emitting an event for each detected change
+func WatchThread(ctx context.Context, postID string, interval time.Duration) <-chan PostEvent {
+
+	watchlist := NewWatchlist(interval)
+
+	watchlist.Add(postID)
+
+	return watchlist.Watch(ctx)
+}
+
+// WatchThreadVelocity re-fetch a single post with its comments until the context is cancelled, emitting an event for each detected change and a velocity event each time its comment velocity over window crosses the thresholds
+func WatchThreadVelocity(ctx context.Context, postID string, interval, window time.Duration, minCommentsPerMinute float64, minUniqueCommenters int) <-chan PostEvent {
+
+	watchlist := NewWatchlist(interval)
+
+	watchlist.Window = window
+	watchlist.MinCommentsPerMinute = minCommentsPerMinute
+	watchlist.MinUniqueCommenters = minUniqueCommenters
+
+	watchlist.Add(postID)
+
+	return watchlist.Watch(ctx)
+}
+
+func postFullname(postID string) string {
+
+	return string(AppendFullname(make([]byte, 0, len(apiObjectTypePost)+1+len(postID)), apiObjectTypePost, postID))
+}
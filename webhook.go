@@ -0,0 +1,260 @@
+package rscraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// webhookQueueSize the number of items a webhook sink queues per concurrent delivery before Write blocks
+const webhookQueueSize = 16
+
+// WebhookSink posts each item as JSON to a webhook URL. Deliveries run concurrently up to a limit and are spaced out to respect the endpoint's rate limit; when deliveries fall behind, Write blocks instead of buffering without bound. Deliveries that fail permanently are written to a dead-letter file
+type WebhookSink struct {
+	URL string
+
+	// MaxAttempts the number of times a delivery is attempted before it is dead-lettered
+	MaxAttempts int
+
+	queue      chan interface{}
+	deadLetter *json.Encoder
+	client     *http.Client
+
+	mutex   sync.Mutex
+	drained *sync.Cond
+	pending int
+	err     error
+	closed  bool
+
+	closeOnce sync.Once
+}
+
+// WebhookDeadLetter a delivery that failed permanently
+type WebhookDeadLetter struct {
+	URL      string      `json:"url"`
+	Item     interface{} `json:"item"`
+	Error    string      `json:"error"`
+	Attempts int         `json:"attempts"`
+	FailedAt time.Time   `json:"failed_at"`
+}
+
+var (
+	webhookLimitMutex sync.Mutex
+	webhookNextSlot   = make(map[string]time.Time)
+)
+
+// NewWebhookSink create a new sink posting to url with up to concurrency deliveries in flight, at most one delivery per interval to the same URL across all webhook sinks, and permanently failing deliveries written as newline-delimited JSON to deadLetter. When deadLetter is nil, the first permanent failure is returned by Flush instead. Call Close when done to stop the delivery workers
+func NewWebhookSink(url string, concurrency int, interval time.Duration, deadLetter io.Writer) *WebhookSink {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	result := &WebhookSink{URL: url, MaxAttempts: batchMaxAttempts, queue: make(chan interface{}, concurrency*webhookQueueSize), client: &http.Client{Timeout: 30 * time.Second}}
+
+	result.drained = sync.NewCond(&result.mutex)
+
+	if deadLetter != nil {
+		result.deadLetter = json.NewEncoder(deadLetter)
+	}
+
+	for i := 0; i < concurrency; i++ {
+		go result.deliverAll(interval)
+	}
+
+	return result
+}
+
+// Write queue items for delivery, blocking while the queue is full. Returns an error once the sink is closed
+func (me *WebhookSink) Write(items ...interface{}) error {
+
+	for _, item := range items {
+
+		me.mutex.Lock()
+
+		if me.closed {
+			me.mutex.Unlock()
+			return errors.New("Webhook sink is closed")
+		}
+
+		me.pending++
+		me.mutex.Unlock()
+
+		me.queue <- item
+	}
+
+	return nil
+}
+
+// Flush wait until every queued item has been delivered or dead-lettered
+func (me *WebhookSink) Flush() error {
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for me.pending > 0 {
+		me.drained.Wait()
+	}
+
+	err := me.err
+	me.err = nil
+
+	return err
+}
+
+// Close deliver the queued items and stop the delivery workers. Writes made afterwards return an error
+func (me *WebhookSink) Close() error {
+
+	me.mutex.Lock()
+	me.closed = true
+	me.mutex.Unlock()
+
+	err := me.Flush()
+
+	me.closeOnce.Do(func() {
+		close(me.queue)
+	})
+
+	return err
+}
+
+func (me *WebhookSink) deliverAll(interval time.Duration) {
+
+	for item := range me.queue {
+
+		attempts, err := me.deliver(item, interval)
+
+		me.mutex.Lock()
+
+		if err != nil {
+			me.deadLetterItem(item, attempts, err)
+		}
+
+		me.pending--
+
+		if me.pending == 0 {
+			me.drained.Broadcast()
+		}
+
+		me.mutex.Unlock()
+	}
+}
+
+// deliver post an item, retrying failures that may be temporary. Returns the number of attempts made and the final error, if any
+func (me *WebhookSink) deliver(item interface{}, interval time.Duration) (int, error) {
+
+	body, err := json.Marshal(item)
+
+	if err != nil {
+		return 0, err
+	}
+
+	delay := batchRetryDelay
+
+	attempts := me.MaxAttempts
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		waitForWebhookSlot(me.URL, interval)
+
+		retry, err := me.post(body)
+
+		if err == nil || !retry || attempt >= attempts {
+			return attempt, err
+		}
+
+		<-after(delay)
+		delay *= 2
+	}
+}
+
+// post send a delivery, reporting whether a failure is worth retrying
+func (me *WebhookSink) post(body []byte) (bool, error) {
+
+	req, err := http.NewRequest("POST", me.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", apiUserAgent)
+
+	resp, err := me.client.Do(req)
+
+	if err != nil {
+		return true, redactError(err)
+	}
+
+	defer resp.Body.Close()
+
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+
+	return retry, fmt.Errorf("Webhook returned %d", resp.StatusCode)
+}
+
+// deadLetterItem record a permanently failed delivery. Callers hold the mutex
+func (me *WebhookSink) deadLetterItem(item interface{}, attempts int, err error) {
+
+	if me.deadLetter != nil {
+
+		entry := WebhookDeadLetter{URL: RedactURL(me.URL), Item: item, Error: Redact(err.Error()), Attempts: attempts, FailedAt: now().UTC()}
+
+		if encodeErr := me.deadLetter.Encode(entry); encodeErr == nil {
+			return
+		}
+	}
+
+	if me.err == nil {
+		me.err = err
+	}
+}
+
+// waitForWebhookSlot wait until a delivery to url may be made, reserving the slot interval after it for the next delivery. Slots that have passed are forgotten, so only URLs with a delivery reserved are remembered
+func waitForWebhookSlot(url string, interval time.Duration) {
+
+	if interval <= 0 {
+		return
+	}
+
+	webhookLimitMutex.Lock()
+
+	current := now()
+
+	slot := webhookNextSlot[url]
+
+	if slot.Before(current) {
+
+		slot = current
+
+		for key, next := range webhookNextSlot {
+			if !next.After(current) {
+				delete(webhookNextSlot, key)
+			}
+		}
+	}
+
+	webhookNextSlot[url] = slot.Add(interval)
+
+	webhookLimitMutex.Unlock()
+
+	if wait := slot.Sub(current); wait > 0 {
+		<-after(wait)
+	}
+}
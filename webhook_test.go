@@ -0,0 +1,54 @@
+package rscraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSinkWriteAfterClose(t *testing.T) {
+
+	delivered := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, 1, 0, nil)
+
+	if err := sink.Write(1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if delivered != 2 {
+		t.Fatalf("Expected 2 deliveries before closing, got %d", delivered)
+	}
+
+	if err := sink.Write(3); err == nil {
+		t.Fatal("Expected writing to a closed sink to fail")
+	}
+}
+
+func TestWebhookSlotsForgotten(t *testing.T) {
+
+	for _, url := range []string{"https://a.example", "https://b.example", "https://c.example"} {
+		waitForWebhookSlot(url, time.Nanosecond)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	waitForWebhookSlot("https://d.example", time.Nanosecond)
+
+	webhookLimitMutex.Lock()
+	defer webhookLimitMutex.Unlock()
+
+	if len(webhookNextSlot) != 1 {
+		t.Fatalf("Expected passed slots to be forgotten, %d remembered", len(webhookNextSlot))
+	}
+}